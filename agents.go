@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent is a named prompt + model + toolset profile: a reusable persona
+// that controls how a subreddit gets summarized (system prompt, Anthropic
+// model, temperature, max_tokens, fetch limits, and summary header),
+// independent of any single user's global model choice. Agents are looked
+// up by Codename via /agent or a per-message "<codename> r/<subreddit>"
+// prefix.
+type Agent struct {
+	Codename      string  `json:"codename" yaml:"codename"`
+	Name          string  `json:"name" yaml:"name"`
+	SystemPrompt  string  `json:"system_prompt" yaml:"system_prompt"`
+	Model         string  `json:"model" yaml:"model"`
+	Temperature   float64 `json:"temperature" yaml:"temperature"`
+	MaxTokens     int     `json:"max_tokens" yaml:"max_tokens"`
+	PostLimit     int     `json:"post_limit" yaml:"post_limit"`
+	CommentLimit  int     `json:"comment_limit" yaml:"comment_limit"`
+	SummaryHeader string  `json:"summary_header" yaml:"summary_header"`
+}
+
+// defaultAgents are the built-in agents used when Config.AgentsFilePath is
+// unset or fails to load: a spread of personas from quick and plain-spoken
+// to thorough and analytical, so the bot is useful without any extra
+// configuration.
+func defaultAgents() []Agent {
+	return []Agent{
+		{
+			Codename:      "news-brief",
+			Name:          "News Brief",
+			SystemPrompt:  "You are a news desk editor. Summarize the following Reddit posts and comments from r/%s as a tight, neutral news brief: lead with what happened, then why it matters. No fluff, no personal opinions.",
+			Model:         "claude-3-haiku-20240307",
+			Temperature:   0.3,
+			MaxTokens:     1000,
+			PostLimit:     7,
+			CommentLimit:  5,
+			SummaryHeader: "📰 *NEWS BRIEF* 📰\n\n",
+		},
+		{
+			Codename:      "deep-dive",
+			Name:          "Deep Dive",
+			SystemPrompt:  "You are an analyst. Give a thorough, structured breakdown of these Reddit posts and comments from r/%s: main themes with supporting evidence from specific posts, dissenting views, and what's likely to develop next.",
+			Model:         "claude-3-opus-20240229",
+			Temperature:   0.5,
+			MaxTokens:     2500,
+			PostLimit:     10,
+			CommentLimit:  10,
+			SummaryHeader: "🔬 *DEEP DIVE* 🔬\n\n",
+		},
+		{
+			Codename:      "eli5",
+			Name:          "Explain Like I'm 5",
+			SystemPrompt:  "Explain what's happening in these Reddit posts and comments from r/%s in simple, friendly language a complete newcomer to the topic could follow. Avoid jargon; use short sentences and everyday analogies.",
+			Model:         "claude-3-haiku-20240307",
+			Temperature:   0.6,
+			MaxTokens:     800,
+			PostLimit:     5,
+			CommentLimit:  5,
+			SummaryHeader: "🧒 *ELI5* 🧒\n\n",
+		},
+		{
+			Codename:      "sentiment",
+			Name:          "Sentiment Check",
+			SystemPrompt:  "Focus on community sentiment in these Reddit posts and comments from r/%s: is the mood positive, negative, or mixed, what's driving it, and where opinions diverge sharply.",
+			Model:         "claude-3-sonnet-20240229",
+			Temperature:   0.4,
+			MaxTokens:     1200,
+			PostLimit:     7,
+			CommentLimit:  8,
+			SummaryHeader: "📊 *SENTIMENT CHECK* 📊\n\n",
+		},
+	}
+}
+
+// LoadAgents reads agent profiles from path (YAML for a .yaml/.yml
+// extension, JSON otherwise). An empty path, a missing file, or a parse
+// error all fall back to defaultAgents(), since a malformed agents file
+// shouldn't take the bot down.
+func LoadAgents(path string) []Agent {
+	if path == "" {
+		return defaultAgents()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("WARNING: Failed to read agents file %s, using built-in agents: %v", path, err)
+		return defaultAgents()
+	}
+
+	var agents []Agent
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &agents)
+	default:
+		err = json.Unmarshal(data, &agents)
+	}
+	if err != nil {
+		log.Printf("WARNING: Failed to parse agents file %s, using built-in agents: %v", path, err)
+		return defaultAgents()
+	}
+	if len(agents) == 0 {
+		log.Printf("WARNING: Agents file %s defined no agents, using built-in agents", path)
+		return defaultAgents()
+	}
+
+	return agents
+}
+
+// findAgent returns the agent in agents whose Codename matches codename
+// (case-insensitive), or false if none match.
+func findAgent(agents []Agent, codename string) (Agent, bool) {
+	for _, agent := range agents {
+		if strings.EqualFold(agent.Codename, codename) {
+			return agent, true
+		}
+	}
+	return Agent{}, false
+}
+
+// summarizeWithAgent renders agent's system prompt against subredditName
+// and calls the Anthropic API directly with agent's own model,
+// temperature, and max_tokens, tagging the result with agent's summary
+// header instead of AppConfig's global defaults. This is what makes an
+// agent's profile actually take effect, rather than just labeling a
+// summary produced the usual way.
+func summarizeWithAgent(ctx context.Context, agent Agent, subredditName, text string) (string, error) {
+	ctx = withSubreddit(ctx, subredditName)
+	ctx = withModel(ctx, agent.Model)
+	loggerFromContext(ctx).Info("Making Anthropic API call", "agent", agent.Codename)
+
+	if AppConfig.AnthropicAPIKey == "" {
+		return "", fmt.Errorf("Anthropic API key is not configured")
+	}
+
+	systemPrompt := agent.SystemPrompt
+	if strings.Contains(systemPrompt, "%s") {
+		systemPrompt = fmt.Sprintf(systemPrompt, subredditName)
+	}
+
+	request := AnthropicRequest{
+		Model: agent.Model,
+		Messages: []Message{
+			{
+				Role:    "user",
+				Content: fmt.Sprintf("%s\n\nPosts to analyze:\n\n%s", systemPrompt, text),
+			},
+		},
+		MaxTokens:   agent.MaxTokens,
+		Temperature: agent.Temperature,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, AppConfig.AnthropicRequestTimeout)
+	defer cancel()
+
+	response, err := makeAnthropicAPICall(ctx, request, AppConfig.AnthropicAPIKey)
+	if err != nil {
+		return "", fmt.Errorf("API call failed: %w", err)
+	}
+
+	summary, err := formatResponseWithHeader(response, agent.SummaryHeader)
+	if err != nil {
+		return "", err
+	}
+	return summary, nil
+}
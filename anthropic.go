@@ -3,6 +3,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -63,6 +64,19 @@ type AnthropicRequest struct {
 	Messages    []Message `json:"messages"`
 	MaxTokens   int       `json:"max_tokens"`
 	Temperature float64   `json:"temperature"`
+	Stream      bool      `json:"stream,omitempty"`
+}
+
+// anthropicStreamEvent is the subset of Anthropic's SSE event payload we care
+// about: the incremental text delta of a content_block_delta event.
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
 }
 
 // AnthropicResponse represents the structure of a response from the Anthropic API
@@ -73,11 +87,25 @@ type AnthropicResponse struct {
 	Error *struct {
 		Message string `json:"error,omitempty"`
 	} `json:"error,omitempty"`
+	Usage *AnthropicUsage `json:"usage,omitempty"`
+}
+
+// AnthropicUsage reports the token accounting the Anthropic API returns
+// alongside a message, used to populate the llm_tokens_total metric.
+type AnthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
-// summarizePosts takes a string of Reddit posts and returns a summarized version using the Anthropic API
-func summarizePosts(subreddit, text string, model string) (string, error) {
-	log.Printf("INFO: Making Anthropic API call with model: %s", model)
+// summarizePosts takes a string of Reddit posts and returns a summarized
+// version using the Anthropic API. ctx is honored for the outbound API
+// call, so canceling it (e.g. a web server draining in-flight requests at
+// shutdown) aborts the summary instead of letting it run to completion in
+// the background.
+func summarizePosts(ctx context.Context, subreddit, text string, model string) (string, error) {
+	ctx = withSubreddit(ctx, subreddit)
+	ctx = withModel(ctx, model)
+	loggerFromContext(ctx).Info("Making Anthropic API call")
 
 	if AppConfig.AnthropicAPIKey == "" {
 		return "", fmt.Errorf("Anthropic API key is not configured")
@@ -86,8 +114,9 @@ func summarizePosts(subreddit, text string, model string) (string, error) {
 	// Prepare the API request
 	request := createAnthropicRequest(model, text, subreddit)
 
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), AppConfig.AnthropicRequestTimeout)
+	// Bound the API call by the configured timeout, but still under ctx so
+	// the caller's own cancellation takes effect too.
+	ctx, cancel := context.WithTimeout(ctx, AppConfig.AnthropicRequestTimeout)
 	defer cancel()
 
 	// Make the API call
@@ -100,6 +129,93 @@ func summarizePosts(subreddit, text string, model string) (string, error) {
 	return formatResponse(response)
 }
 
+// summarizePostsStream is the streaming counterpart to summarizePosts. It
+// issues the request with "stream": true and emits each content_block_delta
+// fragment on the returned text channel as it arrives, closing both channels
+// when the stream ends. Callers should treat anything on the error channel
+// as fatal and fall back to summarizePosts.
+func summarizePostsStream(ctx context.Context, subreddit, text, model string) (<-chan string, <-chan error) {
+	fragments := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(fragments)
+		defer close(errs)
+
+		if AppConfig.AnthropicAPIKey == "" {
+			errs <- fmt.Errorf("Anthropic API key is not configured")
+			return
+		}
+
+		request := createAnthropicRequest(model, text, subreddit)
+		request.Stream = true
+
+		if err := anthropicLimiter.Wait(ctx); err != nil {
+			errs <- fmt.Errorf("rate limit wait failed: %w", err)
+			return
+		}
+
+		requestBody, err := json.Marshal(request)
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", AppConfig.AnthropicAPIEndpoint, bytes.NewBuffer(requestBody))
+		if err != nil {
+			errs <- fmt.Errorf("failed to create HTTP request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", AppConfig.AnthropicAPIKey)
+		req.Header.Set("anthropic-version", AppConfig.AnthropicAPIVersion)
+		req.Header.Set("Accept", "text/event-stream")
+
+		client := &http.Client{Timeout: AppConfig.AnthropicRequestTimeout}
+		resp, err := client.Do(req)
+		if err != nil {
+			errs <- fmt.Errorf("HTTP request failed: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("API returned non-200 status code %d: %s", resp.StatusCode, string(bodyBytes))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			var event anthropicStreamEvent
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event); err != nil {
+				log.Printf("WARNING: Failed to parse Anthropic stream event: %v", err)
+				continue
+			}
+
+			if event.Error != nil && event.Error.Message != "" {
+				errs <- fmt.Errorf("API error: %s", event.Error.Message)
+				return
+			}
+
+			if event.Type == "content_block_delta" && event.Delta.Text != "" {
+				fragments <- event.Delta.Text
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("error reading stream: %w", err)
+		}
+	}()
+
+	return fragments, errs
+}
+
 // createAnthropicRequest creates a request structure for the Anthropic API
 func createAnthropicRequest(model, text, subredditName string) AnthropicRequest {
 	// Format the prompt with the Reddit data and subreddit name
@@ -144,9 +260,7 @@ func makeAnthropicAPICall(ctx context.Context, request AnthropicRequest, apiKey
 	req.Header.Set("anthropic-version", AppConfig.AnthropicAPIVersion)
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: AppConfig.AnthropicRequestTimeout,
-	}
+	client := instrumentedClient(AppConfig.AnthropicRequestTimeout)
 
 	// Send the request
 	startTime := time.Now()
@@ -177,11 +291,23 @@ func makeAnthropicAPICall(ctx context.Context, request AnthropicRequest, apiKey
 		return nil, fmt.Errorf("API error: %s", response.Error.Message)
 	}
 
+	if response.Usage != nil {
+		recordLLMTokens(request.Model, response.Usage.InputTokens, response.Usage.OutputTokens)
+	}
+
 	return &response, nil
 }
 
-// formatResponse extracts and formats the text from the Anthropic API response
+// formatResponse extracts and formats the text from the Anthropic API
+// response, prefixed with AppConfig.SummaryHeader.
 func formatResponse(response *AnthropicResponse) (string, error) {
+	return formatResponseWithHeader(response, AppConfig.SummaryHeader)
+}
+
+// formatResponseWithHeader is formatResponse with an explicit header,
+// for callers (like an Agent's summary pipeline) that use their own
+// header instead of AppConfig.SummaryHeader.
+func formatResponseWithHeader(response *AnthropicResponse, header string) (string, error) {
 	if response == nil {
 		return "", fmt.Errorf("nil response received")
 	}
@@ -207,5 +333,5 @@ func formatResponse(response *AnthropicResponse) (string, error) {
 	}
 
 	// Format the response with a header
-	return AppConfig.SummaryHeader + text, nil
+	return header + text, nil
 }
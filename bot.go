@@ -2,9 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,8 +30,96 @@ type DiscordBot struct {
 	userSessions map[string]*UserSession
 	sessionMutex sync.RWMutex
 	stopChan     chan struct{}
+
+	// pages holds the embed pages for an in-flight analysis result, keyed by
+	// the Discord message ID that carries the pagination buttons.
+	pages      map[string]*analysisPages
+	pagesMutex sync.Mutex
+
+	// gateway tracks connection health so Start's reconnect supervisor can
+	// detect zombied connections and resume instead of re-identifying.
+	gatewayMutex  sync.Mutex
+	lastHeartbeat time.Time
+
+	// commandConfig holds per-guild channel allow-lists and cooldowns.
+	commandConfig *CommandConfig
+
+	// devGuildID, when set, scopes slash command registration to a single
+	// guild for near-instant propagation during development. Empty means
+	// commands are registered globally.
+	devGuildID string
+
+	// removeCommandsOnShutdown, when true, makes Stop delete every command
+	// this bot registered via registerCommands.
+	removeCommandsOnShutdown bool
+	registeredCommands       []*discordgo.ApplicationCommand
+
+	// scheduler runs recurring /subscribe digests.
+	scheduler *SubscriptionScheduler
+}
+
+// gatewayBackoffMin/Max bound the jittered exponential backoff used between
+// reconnect attempts in Start's supervisor loop.
+const (
+	gatewayBackoffMin    = 1 * time.Second
+	gatewayBackoffMax    = 60 * time.Second
+	gatewayBackoffFactor = 2.0
+)
+
+// gatewayState is the persisted session/sequence pair that lets a process
+// restart within Discord's resume window resume instead of re-identifying.
+type gatewayState struct {
+	SessionID    string `json:"session_id"`
+	LastSequence int64  `json:"last_sequence"`
+}
+
+var gatewayStateFilePath = filepath.Join("data", "gateway.json")
+
+// fatalCloseCodes are gateway close codes that should not be retried; the
+// caller's context is canceled instead of reconnecting.
+var fatalCloseCodes = map[int]bool{
+	4004: true, // authentication failed
+	4010: true, // invalid shard
+	4011: true, // sharding required
+	4012: true, // invalid API version
+	4013: true, // invalid intents
+	4014: true, // disallowed intents
+}
+
+// nextGatewayBackoff returns the next jittered backoff delay, doubling the
+// previous delay up to gatewayBackoffMax.
+func nextGatewayBackoff(prev time.Duration) time.Duration {
+	next := time.Duration(float64(prev) * gatewayBackoffFactor)
+	if next > gatewayBackoffMax {
+		next = gatewayBackoffMax
+	}
+	if next < gatewayBackoffMin {
+		next = gatewayBackoffMin
+	}
+	jitter := time.Duration(rand.Int63n(int64(next) / 2))
+	return next/2 + jitter
+}
+
+// analysisPages holds the paginated embeds for a single /trend result so the
+// Prev/Next/Jump buttons in interactionCreate can look up the right page.
+// createdAt backs pageTTL eviction, since Discord's interaction tokens (and
+// so the buttons themselves) stop working after ~15 minutes anyway.
+type analysisPages struct {
+	subreddit string
+	embeds    []*discordgo.MessageEmbed
+	current   int
+	createdAt time.Time
 }
 
+// pageTTL bounds how long a paginated result's buttons stay usable before
+// bot.pages evicts it, so a long-running bot's page map doesn't grow
+// unbounded across every /trend result it has ever rendered.
+const pageTTL = 15 * time.Minute
+
+// maxJumpOptions caps how many page numbers the jump-to-page select menu
+// offers, matching Discord's 25-option-per-select-menu limit.
+const maxJumpOptions = 25
+
 // Available models for selection
 var availableModels = []ModelInfo{
 	{
@@ -71,11 +163,25 @@ func NewDiscordBot() (*DiscordBot, error) {
 	// Set intents
 	dg.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
 
+	// Restore gateway session/sequence so a restart within the resume window
+	// resumes rather than doing a full re-identify.
+	var state gatewayState
+	if err := ReadJSONFile(gatewayStateFilePath, &state); err != nil {
+		log.Printf("WARNING: Failed to read gateway state: %v", err)
+	} else if state.SessionID != "" {
+		dg.Identify.SessionID = state.SessionID
+		dg.Identify.Sequence = state.LastSequence
+		log.Printf("INFO: Restored gateway session %s at sequence %d", state.SessionID, state.LastSequence)
+	}
+
 	bot := &DiscordBot{
 		session:      dg,
 		userSessions: make(map[string]*UserSession),
-		stopChan:     make(chan struct{}),
+		stopChan:      make(chan struct{}),
+		pages:         make(map[string]*analysisPages),
+		commandConfig: NewCommandConfig(),
 	}
+	bot.scheduler = NewSubscriptionScheduler(bot)
 
 	// Load existing sessions
 	bot.loadSessions()
@@ -88,13 +194,12 @@ func NewDiscordBot() (*DiscordBot, error) {
 	return bot, nil
 }
 
-// Start starts the Discord bot
+// Start starts the Discord bot, supervising the gateway connection with
+// resume-aware reconnects, jittered exponential backoff, and zombied
+// connection detection for the lifetime of ctx.
 func (bot *DiscordBot) Start(ctx context.Context) error {
-	log.Println("Opening Discord connection...")
-
-	err := bot.session.Open()
-	if err != nil {
-		return fmt.Errorf("error opening Discord connection: %w", err)
+	if err := bot.openGateway(); err != nil {
+		return err
 	}
 
 	// Register slash commands
@@ -102,15 +207,133 @@ func (bot *DiscordBot) Start(ctx context.Context) error {
 		log.Printf("Error registering commands: %v", err)
 	}
 
-	// Wait for context cancellation or stop signal
+	watchdogCtx, cancelWatchdog := context.WithCancel(ctx)
+	defer cancelWatchdog()
+	go bot.heartbeatWatchdog(watchdogCtx, cancelWatchdog)
+	go bot.scheduler.Run(bot.stopChan)
+	go bot.evictExpiredPages(ctx)
+
+	// Wait for context cancellation, a fatal gateway close (watchdogCtx is
+	// canceled independently of ctx in that case), or a stop signal.
 	select {
 	case <-ctx.Done():
 		return ctx.Err()
+	case <-watchdogCtx.Done():
+		return fmt.Errorf("gateway connection failed fatally: %w", watchdogCtx.Err())
 	case <-bot.stopChan:
 		return nil
 	}
 }
 
+// openGateway opens the Discord session and records the initial heartbeat
+// timestamp used by heartbeatWatchdog to detect zombied connections.
+func (bot *DiscordBot) openGateway() error {
+	log.Println("Opening Discord connection...")
+
+	if err := bot.session.Open(); err != nil {
+		return fmt.Errorf("error opening Discord connection: %w", err)
+	}
+
+	bot.gatewayMutex.Lock()
+	bot.lastHeartbeat = time.Now()
+	bot.gatewayMutex.Unlock()
+
+	bot.saveGatewayState()
+	return nil
+}
+
+// saveGatewayState persists the current session ID and sequence so a process
+// restart within Discord's resume window can resume instead of re-identifying.
+func (bot *DiscordBot) saveGatewayState() {
+	state := gatewayState{
+		SessionID:    bot.session.Identify.SessionID,
+		LastSequence: bot.session.Identify.Sequence,
+	}
+	if err := WriteJSONFile(gatewayStateFilePath, state); err != nil {
+		log.Printf("WARNING: Failed to persist gateway state: %v", err)
+	}
+}
+
+// heartbeatWatchdog periodically checks that heartbeat ACKs are still
+// arriving, sourcing the timestamp from discordgo's own
+// session.LastHeartbeatAck rather than anything we stamp ourselves, so an
+// actually healthy connection is never mistaken for a zombie. If two
+// heartbeat intervals pass without an ACK, the connection is treated as
+// zombied: it is force-closed and reopened with a jittered exponential
+// backoff. If reopening fails because Discord rejected our credentials,
+// handleGatewayClose cancels cancel instead of retrying forever.
+func (bot *DiscordBot) heartbeatWatchdog(ctx context.Context, cancel context.CancelFunc) {
+	const heartbeatInterval = 41250 * time.Millisecond // Discord's typical gateway interval
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	backoff := time.Duration(0)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// LastHeartbeatAck is written by discordgo's own heartbeat
+			// goroutine under the session's embedded RWMutex, so it must be
+			// read under that same lock rather than bot.gatewayMutex, which
+			// only protects our own lastHeartbeat/reconnect bookkeeping.
+			bot.session.RLock()
+			ack := bot.session.LastHeartbeatAck
+			bot.session.RUnlock()
+
+			bot.gatewayMutex.Lock()
+			if ack.After(bot.lastHeartbeat) {
+				bot.lastHeartbeat = ack
+			}
+			stale := time.Since(bot.lastHeartbeat) > 2*heartbeatInterval
+			bot.gatewayMutex.Unlock()
+
+			if !stale {
+				backoff = 0
+				continue
+			}
+
+			log.Printf("WARNING: No heartbeat ACK for %v, treating connection as zombied", 2*heartbeatInterval)
+			bot.session.Close()
+
+			backoff = nextGatewayBackoff(backoff)
+			log.Printf("INFO: Reconnecting gateway in %v", backoff)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			if err := bot.openGateway(); err != nil {
+				log.Printf("ERROR: Failed to reopen gateway: %v", err)
+				var restErr *discordgo.RESTError
+				if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusUnauthorized {
+					handleGatewayClose(4004, cancel)
+					return
+				}
+				continue
+			}
+			bot.gatewayMutex.Lock()
+			bot.lastHeartbeat = time.Now()
+			bot.gatewayMutex.Unlock()
+		}
+	}
+}
+
+// handleGatewayClose classifies a gateway close code and either cancels the
+// supervising context (fatal, non-resumable) or signals that a reconnect
+// should be attempted.
+func handleGatewayClose(code int, cancel context.CancelFunc) {
+	if fatalCloseCodes[code] {
+		log.Printf("ERROR: Gateway closed with fatal code %d, not reconnecting", code)
+		cancel()
+		return
+	}
+	log.Printf("INFO: Gateway closed with code %d, will reconnect", code)
+}
+
 // Stop gracefully stops the Discord bot
 func (bot *DiscordBot) Stop(ctx context.Context) error {
 	log.Println("Stopping Discord bot...")
@@ -118,6 +341,13 @@ func (bot *DiscordBot) Stop(ctx context.Context) error {
 	// Signal the bot to stop
 	close(bot.stopChan)
 
+	if bot.removeCommandsOnShutdown {
+		bot.removeCommands()
+	}
+
+	// Persist the gateway state so a future restart can resume
+	bot.saveGatewayState()
+
 	// Close Discord session
 	if err := bot.session.Close(); err != nil {
 		return fmt.Errorf("error closing Discord session: %w", err)
@@ -145,6 +375,25 @@ func (bot *DiscordBot) registerCommands() error {
 					Description: "The subreddit to analyze (without r/)",
 					Required:    true,
 				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "sort",
+					Description: "How to rank posts (default: top)",
+					Required:    false,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "hot", Value: string(SortHot)},
+						{Name: "new", Value: string(SortNew)},
+						{Name: "rising", Value: string(SortRising)},
+						{Name: "top", Value: string(SortTop)},
+						{Name: "controversial", Value: string(SortControversial)},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "timeframe",
+					Description: "Time window for top/controversial posts (hour/day/week/month/year/all)",
+					Required:    false,
+				},
 			},
 		},
 		{
@@ -173,6 +422,18 @@ func (bot *DiscordBot) registerCommands() error {
 				},
 			},
 		},
+		{
+			Name:        "post",
+			Description: "Summarize a single Reddit post by URL or ID",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "url",
+					Description: "A reddit.com/r/.../comments/... link, a redd.it link, or a t3_ fullname",
+					Required:    true,
+				},
+			},
+		},
 		{
 			Name:        "history",
 			Description: "View your subreddit analysis history",
@@ -181,20 +442,44 @@ func (bot *DiscordBot) registerCommands() error {
 			Name:        "clear",
 			Description: "Clear your analysis history",
 		},
+		configCommand,
+		subscribeCommand,
+		unsubscribeCommand,
+		subscriptionsCommand,
+	}
+
+	if bot.devGuildID != "" {
+		log.Printf("Registering slash commands to dev guild %s...", bot.devGuildID)
+	} else {
+		log.Println("Registering slash commands globally...")
 	}
 
-	log.Println("Registering slash commands...")
+	bot.registeredCommands = make([]*discordgo.ApplicationCommand, 0, len(commands))
 	for _, cmd := range commands {
-		_, err := bot.session.ApplicationCommandCreate(bot.session.State.User.ID, "", cmd)
+		created, err := bot.session.ApplicationCommandCreate(bot.session.State.User.ID, bot.devGuildID, cmd)
 		if err != nil {
 			return fmt.Errorf("cannot create '%v' command: %w", cmd.Name, err)
 		}
+		bot.registeredCommands = append(bot.registeredCommands, created)
 	}
 	log.Println("Slash commands registered successfully")
 
 	return nil
 }
 
+// removeCommands deletes every command this bot registered via
+// registerCommands. It is called from Stop when --rmcmd is set, so that
+// only commands this process created are removed (no accidental deletion of
+// commands belonging to other apps).
+func (bot *DiscordBot) removeCommands() {
+	log.Println("Removing registered slash commands...")
+	for _, cmd := range bot.registeredCommands {
+		if err := bot.session.ApplicationCommandDelete(bot.session.State.User.ID, bot.devGuildID, cmd.ID); err != nil {
+			log.Printf("Error deleting command '%s': %v", cmd.Name, err)
+		}
+	}
+}
+
 // getUserSession retrieves or creates a user session
 func (bot *DiscordBot) getUserSession(userID string) *UserSession {
 	bot.sessionMutex.Lock()
@@ -273,23 +558,76 @@ func (bot *DiscordBot) messageCreate(s *discordgo.Session, m *discordgo.MessageC
 
 	// Handle simple text commands for backward compatibility
 	if strings.HasPrefix(m.Content, "!trend ") {
-		subreddit := strings.TrimSpace(strings.TrimPrefix(m.Content, "!trend "))
-		if subreddit != "" {
-			bot.handleTrendCommand(s, m.ChannelID, m.Author.ID, subreddit)
+		args := strings.TrimSpace(strings.TrimPrefix(m.Content, "!trend "))
+		if args == "" {
+			return
+		}
+
+		// A single post URL/ID routes to the single-post path instead of a
+		// subreddit listing, so "!trend <url>" works like "/post".
+		if subreddit, postID, ok := PostIDFromURL(args); ok {
+			bot.handlePostCommand(s, m.ChannelID, m.Author.ID, subreddit, postID)
+			return
+		}
+
+		subreddit, sort, timeframe := parseTrendArgs(args)
+		bot.handleTrendCommand(s, m.ChannelID, m.Author.ID, subreddit, sort, timeframe)
+	}
+}
+
+// parseTrendArgs splits a "!trend" argument string into the subreddit name
+// and any trailing "sort:hot"/"timeframe:week" flags, e.g.
+// "golang sort:hot timeframe:week" -> ("golang", "hot", "week"). Missing
+// flags come back as "", leaving the caller to apply its own defaults.
+func parseTrendArgs(args string) (subreddit, sort, timeframe string) {
+	for _, field := range strings.Fields(args) {
+		switch {
+		case strings.HasPrefix(field, "sort:"):
+			sort = strings.TrimPrefix(field, "sort:")
+		case strings.HasPrefix(field, "timeframe:"):
+			timeframe = strings.TrimPrefix(field, "timeframe:")
+		default:
+			if subreddit == "" {
+				subreddit = field
+			}
 		}
 	}
+	return subreddit, sort, timeframe
 }
 
-// interactionCreate handler for slash commands
+// interactionCreate handler for slash commands and message component interactions
 func (bot *DiscordBot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.ApplicationCommandData().Name == "trend" {
-		bot.handleTrendSlashCommand(s, i)
-	} else if i.ApplicationCommandData().Name == "model" {
-		bot.handleModelSlashCommand(s, i)
-	} else if i.ApplicationCommandData().Name == "history" {
-		bot.handleHistorySlashCommand(s, i)
-	} else if i.ApplicationCommandData().Name == "clear" {
-		bot.handleClearSlashCommand(s, i)
+	switch i.Type {
+	case discordgo.InteractionMessageComponent:
+		bot.handlePaginationButton(s, i)
+		return
+	case discordgo.InteractionApplicationCommand:
+		name := i.ApplicationCommandData().Name
+
+		if i.Member != nil && !bot.enforceCommandConfig(s, i, name, i.Member.User.ID) {
+			return
+		}
+
+		switch name {
+		case "trend":
+			bot.handleTrendSlashCommand(s, i)
+		case "post":
+			bot.handlePostSlashCommand(s, i)
+		case "model":
+			bot.handleModelSlashCommand(s, i)
+		case "history":
+			bot.handleHistorySlashCommand(s, i)
+		case "clear":
+			bot.handleClearSlashCommand(s, i)
+		case "config":
+			bot.handleConfigSlashCommand(s, i)
+		case "subscribe":
+			bot.handleSubscribeSlashCommand(s, i)
+		case "unsubscribe":
+			bot.handleUnsubscribeSlashCommand(s, i)
+		case "subscriptions":
+			bot.handleSubscriptionsSlashCommand(s, i)
+		}
 	}
 }
 
@@ -304,6 +642,17 @@ func (bot *DiscordBot) handleTrendSlashCommand(s *discordgo.Session, i *discordg
 	subreddit := options[0].StringValue()
 	userID := i.Member.User.ID
 
+	sort := AppConfig.RedditDefaultSort
+	timeframe := AppConfig.RedditTimeFrame
+	for _, opt := range options[1:] {
+		switch opt.Name {
+		case "sort":
+			sort = opt.StringValue()
+		case "timeframe":
+			timeframe = opt.StringValue()
+		}
+	}
+
 	// Respond immediately to acknowledge the command
 	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
@@ -317,11 +666,12 @@ func (bot *DiscordBot) handleTrendSlashCommand(s *discordgo.Session, i *discordg
 	}
 
 	// Handle the analysis in a goroutine
-	go bot.handleTrendAnalysis(s, i.ChannelID, userID, subreddit)
+	go bot.handleTrendAnalysis(s, i.ChannelID, userID, subreddit, sort, timeframe)
 }
 
-// handleTrendCommand handles the trend command (both slash and text)
-func (bot *DiscordBot) handleTrendCommand(s *discordgo.Session, channelID, userID, subreddit string) {
+// handleTrendCommand handles the trend command (both slash and text). An
+// empty sort/timeframe falls back to AppConfig.RedditDefaultSort/RedditTimeFrame.
+func (bot *DiscordBot) handleTrendCommand(s *discordgo.Session, channelID, userID, subreddit, sort, timeframe string) {
 	// Send initial message
 	msg, err := s.ChannelMessageSend(channelID, fmt.Sprintf("🔍 Analyzing r/%s... This may take a moment.", subreddit))
 	if err != nil {
@@ -329,14 +679,20 @@ func (bot *DiscordBot) handleTrendCommand(s *discordgo.Session, channelID, userI
 		return
 	}
 
-	bot.handleTrendAnalysis(s, channelID, userID, subreddit)
+	if sort == "" {
+		sort = AppConfig.RedditDefaultSort
+	}
+	if timeframe == "" {
+		timeframe = AppConfig.RedditTimeFrame
+	}
+	bot.handleTrendAnalysis(s, channelID, userID, subreddit, sort, timeframe)
 
 	// Delete the initial message
 	s.ChannelMessageDelete(channelID, msg.ID)
 }
 
 // handleTrendAnalysis performs the actual subreddit analysis
-func (bot *DiscordBot) handleTrendAnalysis(s *discordgo.Session, channelID, userID, subreddit string) {
+func (bot *DiscordBot) handleTrendAnalysis(s *discordgo.Session, channelID, userID, subreddit, sort, timeframe string) {
 	// Clean subreddit name
 	subreddit = strings.TrimPrefix(subreddit, "r/")
 
@@ -364,7 +720,7 @@ func (bot *DiscordBot) handleTrendAnalysis(s *discordgo.Session, channelID, user
 		return
 	}
 
-	data, err := subredditData(subreddit, token)
+	data, err := subredditDataWithSort(context.Background(), subreddit, ListingSort(sort), timeframe, token)
 	if err != nil {
 		log.Printf("Failed to get subreddit data: %v", err)
 		bot.sendMessage(s, channelID, fmt.Sprintf("❌ Failed to analyze r/%s: %v", subreddit, err))
@@ -372,7 +728,7 @@ func (bot *DiscordBot) handleTrendAnalysis(s *discordgo.Session, channelID, user
 	}
 
 	// Generate summary
-	summary, err := summarizePosts(data, session.Model)
+	summary, err := summarizePosts(context.Background(), subreddit, data, session.Model)
 	if err != nil {
 		log.Printf("Failed to generate summary: %v", err)
 		bot.sendMessage(s, channelID, "❌ Failed to generate AI summary")
@@ -380,33 +736,344 @@ func (bot *DiscordBot) handleTrendAnalysis(s *discordgo.Session, channelID, user
 	}
 
 	// Get post links
-	posts, err := fetchTopPosts(subreddit, token)
+	posts, err := fetchListing(context.Background(), subreddit, ListingSort(sort), timeframe, defaultPostLimit, token)
 	if err != nil {
 		log.Printf("Failed to fetch posts for links: %v", err)
 		posts = []RedditPost{} // Ensure posts is never nil
 	}
 
-	// Format and send response
-	response := bot.formatAnalysisResponse(subreddit, summary, posts)
-	bot.sendLongMessage(s, channelID, response)
+	// Build and send the embed pipeline, paginating if the summary spans
+	// multiple embeds.
+	embeds := bot.buildAnalysisEmbeds(subreddit, summary, posts, session.Model)
+	bot.sendAnalysisEmbeds(s, channelID, subreddit, embeds)
 }
 
-// formatAnalysisResponse formats the analysis response for Discord
-func (bot *DiscordBot) formatAnalysisResponse(subreddit, summary string, posts []RedditPost) string {
-	var builder strings.Builder
+// handlePostSlashCommand handles the /post command, which summarizes a
+// single Reddit post (and its top comments) rather than a subreddit's top-N
+// listing.
+func (bot *DiscordBot) handlePostSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		bot.respondError(s, i, "Please provide a post URL or ID")
+		return
+	}
 
-	builder.WriteString(fmt.Sprintf("## 📈 **r/%s Trends**\n\n", subreddit))
-	builder.WriteString(summary)
-	builder.WriteString("\n\n")
+	url := options[0].StringValue()
+	userID := i.Member.User.ID
+
+	subreddit, postID, ok := PostIDFromURL(url)
+	if !ok {
+		bot.respondError(s, i, "Couldn't recognize that as a Reddit post URL or ID")
+		return
+	}
 
-	if len(posts) > 0 {
-		builder.WriteString("### 🔗 **Top Posts**\n")
-		for _, post := range posts {
-			builder.WriteString(fmt.Sprintf("• [%s](<https://reddit.com%s>)\n", post.Title, post.Permalink))
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: "🔍 Analyzing post... This may take a moment.",
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to interaction: %v", err)
+		return
+	}
+
+	go bot.handlePostAnalysis(s, i.ChannelID, userID, subreddit, postID)
+}
+
+// handlePostCommand handles the "!trend <url>" legacy text variant of /post.
+func (bot *DiscordBot) handlePostCommand(s *discordgo.Session, channelID, userID, subreddit, postID string) {
+	msg, err := s.ChannelMessageSend(channelID, "🔍 Analyzing post... This may take a moment.")
+	if err != nil {
+		log.Printf("Error sending initial message: %v", err)
+		return
+	}
+
+	bot.handlePostAnalysis(s, channelID, userID, subreddit, postID)
+
+	s.ChannelMessageDelete(channelID, msg.ID)
+}
+
+// handlePostAnalysis performs the single-post analysis, reusing the same
+// summarization pipeline handleTrendAnalysis uses for subreddit listings.
+func (bot *DiscordBot) handlePostAnalysis(s *discordgo.Session, channelID, userID, subreddit, postID string) {
+	session := bot.getUserSession(userID)
+
+	token, err := getRedditAccessToken()
+	if err != nil {
+		log.Printf("Failed to get access token: %v", err)
+		bot.sendMessage(s, channelID, "❌ Failed to connect to Reddit API")
+		return
+	}
+
+	data, post, err := singlePostData(context.Background(), subreddit, postID, token)
+	if err != nil {
+		log.Printf("Failed to get post data: %v", err)
+		bot.sendMessage(s, channelID, fmt.Sprintf("❌ Failed to analyze post: %v", err))
+		return
+	}
+
+	summary, err := summarizePosts(context.Background(), subreddit, data, session.Model)
+	if err != nil {
+		log.Printf("Failed to generate summary: %v", err)
+		bot.sendMessage(s, channelID, "❌ Failed to generate AI summary")
+		return
+	}
+
+	title := "post"
+	if subreddit != "" {
+		title = "r/" + subreddit
+	}
+	embeds := bot.buildAnalysisEmbeds(title, summary, []RedditPost{post}, session.Model)
+	bot.sendAnalysisEmbeds(s, channelID, title, embeds)
+}
+
+// embedSentimentColor picks a Discord embed color based on the sentiment
+// keywords the model used in its summary. It defaults to a neutral blue.
+func embedSentimentColor(summary string) int {
+	lower := strings.ToLower(summary)
+	switch {
+	case strings.Contains(lower, "controvers") || strings.Contains(lower, "heated") || strings.Contains(lower, "outrage"):
+		return 0xE74C3C // red
+	case strings.Contains(lower, "hype") || strings.Contains(lower, "exciting") || strings.Contains(lower, "positive"):
+		return 0x2ECC71 // green
+	default:
+		return 0x3498DB // blue
+	}
+}
+
+// maxEmbedFieldValue is the largest chunk of text that fits in a single
+// MessageEmbed field, leaving headroom under Discord's 1024 char limit.
+const maxEmbedFieldValue = 1000
+
+// buildAnalysisEmbeds builds one MessageEmbed per page of the analysis
+// summary, each with a footer noting the model used; the "Top Posts" fields
+// are added to the last page only.
+func (bot *DiscordBot) buildAnalysisEmbeds(subreddit, summary string, posts []RedditPost, model string) []*discordgo.MessageEmbed {
+	color := embedSentimentColor(summary)
+	chunks := splitIntoChunks(summary, maxEmbedFieldValue)
+	if len(chunks) == 0 {
+		chunks = []string{"_No summary available._"}
+	}
+
+	var topPostsText strings.Builder
+	for _, post := range posts {
+		topPostsText.WriteString(fmt.Sprintf("• [%s](<https://reddit.com%s>) — %d pts\n", post.Title, post.Permalink, post.Ups))
+	}
+	topPostsChunks := splitIntoChunks(topPostsText.String(), maxEmbedFieldValue)
+
+	generatedAt := time.Now().Format("15:04:05 MST")
+	embeds := make([]*discordgo.MessageEmbed, 0, len(chunks))
+	for idx, chunk := range chunks {
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("r/%s Trends", subreddit),
+			Description: chunk,
+			Color:       color,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("Model: %s • Generated at %s • Page %d/%d", model, generatedAt, idx+1, len(chunks)),
+			},
+		}
+		// Top Posts only belongs on the last page, alongside the tail of the
+		// summary; each chunk stays under maxEmbedFieldValue so a long list
+		// doesn't trip Discord's 1024-char-per-field limit and silently
+		// fail the whole send.
+		if idx == len(chunks)-1 && topPostsText.Len() > 0 {
+			for chunkIdx, postsChunk := range topPostsChunks {
+				name := "Top Posts"
+				if chunkIdx > 0 {
+					name = "Top Posts (cont.)"
+				}
+				embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+					Name:  name,
+					Value: postsChunk,
+				})
+			}
+		}
+		embeds = append(embeds, embed)
+	}
+
+	return embeds
+}
+
+// splitIntoChunks splits text into pieces no longer than maxLen, breaking on
+// line boundaries where possible.
+func splitIntoChunks(text string, maxLen int) []string {
+	if len(text) <= maxLen {
+		return []string{text}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		if current.Len()+len(line)+1 > maxLen {
+			if current.Len() > 0 {
+				chunks = append(chunks, current.String())
+				current.Reset()
+			}
+		}
+		if len(line) > maxLen {
+			line = line[:maxLen-3] + "..."
+		}
+		if current.Len() > 0 {
+			current.WriteString("\n")
+		}
+		current.WriteString(line)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// sendAnalysisEmbeds sends the first embed page and, when there is more than
+// one page, attaches Prev/Next buttons and a jump-to-page select menu, and
+// registers the pages under the resulting message ID for interactionCreate
+// to serve.
+func (bot *DiscordBot) sendAnalysisEmbeds(s *discordgo.Session, channelID, subreddit string, embeds []*discordgo.MessageEmbed) {
+	if len(embeds) == 0 {
+		bot.sendMessage(s, channelID, "❌ No analysis to display")
+		return
+	}
+
+	msgSend := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embeds[0]}}
+	if len(embeds) > 1 {
+		msgSend.Components = paginationComponents(0, len(embeds))
+	}
+
+	msg, err := s.ChannelMessageSendComplex(channelID, msgSend)
+	if err != nil {
+		log.Printf("Error sending analysis embed: %v", err)
+		return
+	}
+
+	if len(embeds) > 1 {
+		bot.pagesMutex.Lock()
+		bot.pages[msg.ID] = &analysisPages{subreddit: subreddit, embeds: embeds, current: 0, createdAt: time.Now()}
+		bot.pagesMutex.Unlock()
+	}
+}
+
+// evictExpiredPages runs on a ticker to drop bot.pages entries older than
+// pageTTL, so a long-running bot doesn't keep every /trend result it has
+// ever rendered in memory; their buttons stop working once Discord expires
+// the underlying interaction token anyway.
+func (bot *DiscordBot) evictExpiredPages(ctx context.Context) {
+	ticker := time.NewTicker(pageTTL)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := time.Now().Add(-pageTTL)
+			bot.pagesMutex.Lock()
+			for id, pages := range bot.pages {
+				if pages.createdAt.Before(cutoff) {
+					delete(bot.pages, id)
+				}
+			}
+			bot.pagesMutex.Unlock()
 		}
 	}
+}
 
-	return builder.String()
+// paginationComponents builds the Prev/Next button row for page `current` of
+// `total`, disabling buttons that would go out of range, plus a jump-to-page
+// select menu in its own row when there's more than one page to jump to.
+func paginationComponents(current, total int) []discordgo.MessageComponent {
+	rows := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Prev",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "trend_page_prev",
+					Disabled: current == 0,
+				},
+				discordgo.Button{
+					Label:    fmt.Sprintf("Page %d/%d", current+1, total),
+					Style:    discordgo.SecondaryButton,
+					CustomID: "trend_page_label",
+					Disabled: true,
+				},
+				discordgo.Button{
+					Label:    "Next",
+					Style:    discordgo.SecondaryButton,
+					CustomID: "trend_page_next",
+					Disabled: current == total-1,
+				},
+			},
+		},
+	}
+
+	jumpTo := total
+	if jumpTo > maxJumpOptions {
+		jumpTo = maxJumpOptions
+	}
+	options := make([]discordgo.SelectMenuOption, jumpTo)
+	for page := 0; page < jumpTo; page++ {
+		options[page] = discordgo.SelectMenuOption{
+			Label:   fmt.Sprintf("Page %d", page+1),
+			Value:   strconv.Itoa(page),
+			Default: page == current,
+		}
+	}
+	rows = append(rows, discordgo.ActionsRow{
+		Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{
+				CustomID:    "trend_page_jump",
+				Placeholder: "Jump to page...",
+				Options:     options,
+			},
+		},
+	})
+	return rows
+}
+
+// handlePaginationButton advances, rewinds, or jumps the page of a paginated
+// analysis result in response to a button press or jump-menu selection.
+func (bot *DiscordBot) handlePaginationButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	messageID := i.Message.ID
+
+	bot.pagesMutex.Lock()
+	pages, ok := bot.pages[messageID]
+	if !ok {
+		bot.pagesMutex.Unlock()
+		return
+	}
+
+	data := i.MessageComponentData()
+	switch data.CustomID {
+	case "trend_page_prev":
+		if pages.current > 0 {
+			pages.current--
+		}
+	case "trend_page_next":
+		if pages.current < len(pages.embeds)-1 {
+			pages.current++
+		}
+	case "trend_page_jump":
+		if len(data.Values) > 0 {
+			if page, err := strconv.Atoi(data.Values[0]); err == nil && page >= 0 && page < len(pages.embeds) {
+				pages.current = page
+			}
+		}
+	}
+	embed := pages.embeds[pages.current]
+	components := paginationComponents(pages.current, len(pages.embeds))
+	bot.pagesMutex.Unlock()
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating pagination message: %v", err)
+	}
 }
 
 // handleModelSlashCommand handles the /model slash command
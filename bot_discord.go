@@ -0,0 +1,207 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordBackoffMin/Max/Factor set the retry backoff for Discord REST calls:
+// 200ms, doubling each attempt, capped at 2s. nextDiscordBackoff keeps half
+// of each delay fixed and randomizes the other half.
+const (
+	discordBackoffMin    = 200 * time.Millisecond
+	discordBackoffMax    = 2 * time.Second
+	discordBackoffFactor = 2.0
+	discordMaxRetries    = 5
+)
+
+// discordBucket tracks one Discord REST rate-limit bucket, identified by the
+// X-RateLimit-Bucket header Discord returns per route.
+type discordBucket struct {
+	remaining int
+	resetAt   time.Time
+}
+
+// discordRateLimiter serializes outbound Discord REST calls per-route so a
+// burst (e.g. the streaming-analysis edit loop) blocks on its own bucket
+// instead of tripping 429s, and backs off exponentially on 429/5xx errors.
+// It also honors the separate global rate limit Discord applies across all
+// routes when X-RateLimit-Global is set.
+type discordRateLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*discordBucket
+	routeKeys   map[string]string // route -> bucket key, once learned
+	globalReset time.Time
+}
+
+// newDiscordRateLimiter creates an empty discordRateLimiter.
+func newDiscordRateLimiter() *discordRateLimiter {
+	return &discordRateLimiter{
+		buckets:   make(map[string]*discordBucket),
+		routeKeys: make(map[string]string),
+	}
+}
+
+// wait blocks until route's bucket (if known) has budget, and until any
+// active global rate limit has cleared.
+func (rl *discordRateLimiter) wait(route string) {
+	for {
+		rl.mu.Lock()
+		now := time.Now()
+
+		if wait := rl.globalReset.Sub(now); wait > 0 {
+			rl.mu.Unlock()
+			time.Sleep(wait)
+			continue
+		}
+
+		key, known := rl.routeKeys[route]
+		if !known {
+			rl.mu.Unlock()
+			return
+		}
+		bucket := rl.buckets[key]
+		if bucket == nil || bucket.remaining > 0 || now.After(bucket.resetAt) {
+			rl.mu.Unlock()
+			return
+		}
+
+		wait := bucket.resetAt.Sub(now)
+		rl.mu.Unlock()
+		time.Sleep(wait)
+	}
+}
+
+// updateFromResponse records the bucket/remaining/reset-after Discord
+// reported for route, and any global retry-after, from resp's headers.
+func (rl *discordRateLimiter) updateFromResponse(route string, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	if resp.Header.Get("X-RateLimit-Global") == "true" {
+		retryAfter := parseSeconds(resp.Header.Get("Retry-After"))
+		rl.mu.Lock()
+		rl.globalReset = time.Now().Add(retryAfter)
+		rl.mu.Unlock()
+		return
+	}
+
+	bucketKey := resp.Header.Get("X-RateLimit-Bucket")
+	if bucketKey == "" {
+		return
+	}
+
+	remaining, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	resetAfter := parseSeconds(resp.Header.Get("X-RateLimit-Reset-After"))
+
+	rl.mu.Lock()
+	rl.routeKeys[route] = bucketKey
+	rl.buckets[bucketKey] = &discordBucket{
+		remaining: remaining,
+		resetAt:   time.Now().Add(resetAfter),
+	}
+	rl.mu.Unlock()
+}
+
+func parseSeconds(raw string) time.Duration {
+	if raw == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(raw, 64)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// nextDiscordBackoff returns the next jittered backoff delay for attempt n
+// (0-indexed), doubling from discordBackoffMin up to discordBackoffMax.
+func nextDiscordBackoff(attempt int) time.Duration {
+	delay := float64(discordBackoffMin)
+	for i := 0; i < attempt; i++ {
+		delay *= discordBackoffFactor
+	}
+	if delay > float64(discordBackoffMax) {
+		delay = float64(discordBackoffMax)
+	}
+	return time.Duration(delay/2 + rand.Float64()*delay/2)
+}
+
+// do runs fn (a single discordgo REST call) under route's bucket, retrying
+// with exponential backoff on 429/5xx errors up to discordMaxRetries times.
+func (rl *discordRateLimiter) do(route string, fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt <= discordMaxRetries; attempt++ {
+		rl.wait(route)
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		var restErr *discordgo.RESTError
+		if !errors.As(err, &restErr) || restErr.Response == nil {
+			return err
+		}
+
+		rl.updateFromResponse(route, restErr.Response)
+
+		status := restErr.Response.StatusCode
+		if status != 429 && status < 500 {
+			return err
+		}
+
+		log.Printf("WARNING: Discord REST call to %s returned %d, retrying (attempt %d/%d)", route, status, attempt+1, discordMaxRetries)
+		time.Sleep(nextDiscordBackoff(attempt))
+	}
+	return lastErr
+}
+
+// sendMessage wraps session.ChannelMessageSend with rate-limit handling.
+func (rl *discordRateLimiter) sendMessage(s *discordgo.Session, channelID, content string) (*discordgo.Message, error) {
+	var msg *discordgo.Message
+	err := rl.do("POST /channels/messages", func() error {
+		var err error
+		msg, err = s.ChannelMessageSend(channelID, content)
+		return err
+	})
+	return msg, err
+}
+
+// editMessage wraps session.ChannelMessageEdit with rate-limit handling.
+func (rl *discordRateLimiter) editMessage(s *discordgo.Session, channelID, messageID, content string) (*discordgo.Message, error) {
+	var msg *discordgo.Message
+	err := rl.do("PATCH /channels/messages", func() error {
+		var err error
+		msg, err = s.ChannelMessageEdit(channelID, messageID, content)
+		return err
+	})
+	return msg, err
+}
+
+// deleteMessage wraps session.ChannelMessageDelete with rate-limit handling.
+func (rl *discordRateLimiter) deleteMessage(s *discordgo.Session, channelID, messageID string) error {
+	return rl.do("DELETE /channels/messages", func() error {
+		return s.ChannelMessageDelete(channelID, messageID)
+	})
+}
+
+// sendComplex wraps session.ChannelMessageSendComplex with rate-limit handling.
+func (rl *discordRateLimiter) sendComplex(s *discordgo.Session, channelID string, data *discordgo.MessageSend) (*discordgo.Message, error) {
+	var msg *discordgo.Message
+	err := rl.do("POST /channels/messages", func() error {
+		var err error
+		msg, err = s.ChannelMessageSendComplex(channelID, data)
+		return err
+	})
+	return msg, err
+}
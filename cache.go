@@ -0,0 +1,222 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache stores JSON-serializable values under a string key with a TTL.
+// appCache is an in-memory LRU by default, or Redis-backed when REDIS_URL is
+// configured so multiple subtrends instances can share hits.
+type Cache interface {
+	Get(ctx context.Context, key string) (string, bool)
+	Set(ctx context.Context, key string, value string, ttl time.Duration)
+}
+
+// appCache is the process-wide cache used by fetchTopPosts/fetchTopComments
+// and the OpenAI summary lookup. It's initialized by InitializeCache.
+var appCache Cache
+
+// summaryGroup coalesces concurrent requests for the same cache key (e.g.
+// multiple WebSocket clients analyzing the same subreddit at once) into a
+// single upstream call; late arrivals share the first caller's result.
+var summaryGroup singleflight.Group
+
+// InitializeCache sets up appCache from AppConfig: Redis-backed when
+// RedisURL is set, otherwise an in-memory LRU.
+func InitializeCache() {
+	if AppConfig.RedisURL == "" {
+		appCache = newLRUCache(AppConfig.CacheCapacity)
+		return
+	}
+
+	cache, err := newRedisCache(AppConfig.RedisURL)
+	if err != nil {
+		log.Printf("WARNING: Failed to initialize Redis cache (%v), falling back to in-memory LRU", err)
+		appCache = newLRUCache(AppConfig.CacheCapacity)
+		return
+	}
+	appCache = cache
+}
+
+// cacheKeyForListing builds the cache key fetchListing results are stored
+// under, namespaced by the parameters that affect the result.
+func cacheKeyForListing(subreddit string, sort ListingSort, timeframe string, limit int) string {
+	return fmt.Sprintf("reddit:posts:%s:%s:%s:%d", subreddit, sort, timeframe, limit)
+}
+
+// cacheKeyForComments builds the cache key fetchTopComments results are
+// stored under.
+func cacheKeyForComments(permalink string) string {
+	return fmt.Sprintf("reddit:comments:%s:%d", permalink, defaultCommentLimit)
+}
+
+// cacheKeyForSummary builds the cache key a rendered summary is stored
+// under, namespaced by subreddit, model, and a hash of the input text so a
+// changed Reddit thread doesn't serve a stale summary.
+func cacheKeyForSummary(subreddit, model, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("summary:%s:%s:%s", subreddit, model, hex.EncodeToString(sum[:]))
+}
+
+// cacheKeyForSubredditResolve builds the cache key resolveSubredditCandidates
+// results are stored under, namespaced by the user's query so the same typo
+// doesn't hit Reddit's autocomplete endpoint more than once.
+func cacheKeyForSubredditResolve(query string) string {
+	return fmt.Sprintf("reddit:resolve:%s", strings.ToLower(query))
+}
+
+// cacheKeyForAnalysis builds the cache key handleAnalyze's fetch+summarize
+// pipeline result is stored under, namespaced by subreddit and model plus a
+// timestamp bucketed to ttl, so entries roll over on their own without
+// needing an eviction sweep.
+func cacheKeyForAnalysis(subreddit, model string, ttl time.Duration) string {
+	bucket := time.Now().Truncate(ttl).Unix()
+	return fmt.Sprintf("analysis:%s:%s:%d", subreddit, model, bucket)
+}
+
+// getCached fetches key from appCache and unmarshals it into dest, reporting
+// whether a usable cache entry was found.
+func getCached(ctx context.Context, key string, dest interface{}) bool {
+	if appCache == nil {
+		return false
+	}
+	raw, ok := appCache.Get(ctx, key)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal([]byte(raw), dest); err != nil {
+		log.Printf("WARNING: Failed to unmarshal cache entry %s: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// setCached marshals value and stores it in appCache under key for ttl.
+func setCached(ctx context.Context, key string, value interface{}, ttl time.Duration) {
+	if appCache == nil {
+		return
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("WARNING: Failed to marshal cache entry %s: %v", key, err)
+		return
+	}
+	appCache.Set(ctx, key, string(raw), ttl)
+}
+
+// lruEntry is a single cache record tracked by lruCache's eviction list.
+type lruEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// lruCache is a process-local, size-bounded cache with per-entry TTLs. It's
+// the default Cache implementation when REDIS_URL isn't set.
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// newLRUCache creates an lruCache holding at most capacity entries.
+func newLRUCache(capacity int) *lruCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &lruCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache) Get(ctx context.Context, key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return "", false
+	}
+
+	c.order.MoveToFront(el)
+	return entry.value, true
+}
+
+func (c *lruCache) Set(ctx context.Context, key string, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = time.Now().Add(ttl)
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&lruEntry{key: key, value: value, expiresAt: time.Now().Add(ttl)})
+	c.entries[key] = el
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
+
+// redisCache is the Cache implementation used when REDIS_URL is configured,
+// so cache hits are shared across multiple subtrends instances.
+type redisCache struct {
+	client *redis.Client
+}
+
+// newRedisCache connects to redisURL (a redis:// or rediss:// connection
+// string).
+func newRedisCache(redisURL string) (*redisCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse REDIS_URL: %w", err)
+	}
+	return &redisCache{client: redis.NewClient(opts)}, nil
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) (string, bool) {
+	value, err := c.client.Get(ctx, key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Printf("WARNING: Redis GET %s failed: %v", key, err)
+		}
+		return "", false
+	}
+	return value, true
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value string, ttl time.Duration) {
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		log.Printf("WARNING: Redis SET %s failed: %v", key, err)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLRUCacheGetSetAndExpiry(t *testing.T) {
+	c := newLRUCache(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", "1", time.Hour)
+	if v, ok := c.Get(ctx, "a"); !ok || v != "1" {
+		t.Fatalf("expected cached value \"1\", got %q (ok=%v)", v, ok)
+	}
+
+	c.Set(ctx, "b", "2", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if _, ok := c.Get(ctx, "b"); ok {
+		t.Fatal("expected expired entry to be evicted")
+	}
+}
+
+func TestLRUCacheEvictsOldest(t *testing.T) {
+	c := newLRUCache(2)
+	ctx := context.Background()
+
+	c.Set(ctx, "a", "1", time.Hour)
+	c.Set(ctx, "b", "2", time.Hour)
+	c.Set(ctx, "c", "3", time.Hour) // capacity 2, "a" should be evicted
+
+	if _, ok := c.Get(ctx, "a"); ok {
+		t.Fatal("expected oldest entry to be evicted once capacity was exceeded")
+	}
+	if v, ok := c.Get(ctx, "c"); !ok || v != "3" {
+		t.Fatalf("expected most recent entry to survive, got %q (ok=%v)", v, ok)
+	}
+}
+
+func TestCacheKeysVaryWithInputs(t *testing.T) {
+	if cacheKeyForListing("golang", SortTop, "day", 7) == cacheKeyForListing("rust", SortTop, "day", 7) {
+		t.Fatal("expected different subreddits to produce different post cache keys")
+	}
+	if cacheKeyForListing("golang", SortTop, "day", 7) == cacheKeyForListing("golang", SortHot, "day", 7) {
+		t.Fatal("expected different sorts to produce different post cache keys")
+	}
+	if cacheKeyForSummary("golang", "gpt-5-mini", "a") == cacheKeyForSummary("golang", "gpt-5-mini", "b") {
+		t.Fatal("expected different text to produce different summary cache keys")
+	}
+}
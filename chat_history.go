@@ -0,0 +1,485 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
+)
+
+// ChatHistoryEntry records one completed Telegram analysis: the subreddit
+// that was analyzed, the summary text that was returned, and the model and
+// agent used to produce it, so it can be recalled or re-rendered later via
+// /recent, /search, and /resummarize.
+type ChatHistoryEntry struct {
+	UserID    int64
+	Username  string
+	Subreddit string
+	Summary   string
+	Model     string
+	Agent     string
+	Timestamp time.Time
+}
+
+// ChatHistoryStore persists ChatHistoryEntry records on behalf of the
+// Telegram bot. Implementations back onto SQLite (the default, file-backed),
+// MySQL (for multi-instance deployments sharing one database), or a flat
+// JSON-lines file (a dependency-free fallback for single-process setups).
+type ChatHistoryStore interface {
+	// Add records a new entry.
+	Add(entry ChatHistoryEntry) error
+	// Recent returns userID's last n entries, newest first.
+	Recent(userID int64, n int) ([]ChatHistoryEntry, error)
+	// Search returns userID's entries whose subreddit or summary contains
+	// query (case-insensitive), newest first.
+	Search(userID int64, query string) ([]ChatHistoryEntry, error)
+	// Latest returns userID's most recently recorded entry for subreddit, or
+	// nil if there isn't one.
+	Latest(userID int64, subreddit string) (*ChatHistoryEntry, error)
+	// Subreddits returns the distinct subreddits userID has analyzed, most
+	// recently seen first.
+	Subreddits(userID int64) ([]string, error)
+	// Clear deletes every entry recorded for userID.
+	Clear(userID int64) error
+	// Close releases the store's underlying resources.
+	Close() error
+}
+
+// NewChatHistoryStore builds the ChatHistoryStore selected by
+// cfg.HistoryBackend ("sqlite", the default; "mysql"; or "file").
+func NewChatHistoryStore(cfg *Config) (ChatHistoryStore, error) {
+	switch cfg.HistoryBackend {
+	case "mysql":
+		return newSQLChatHistoryStore("mysql", cfg.HistoryDSN, mysqlHistoryTableSQL)
+	case "file":
+		dsn := cfg.HistoryDSN
+		if dsn == "" {
+			dsn = "data/history.jsonl"
+		}
+		return newFileChatHistoryStore(dsn)
+	case "sqlite", "":
+		dsn := cfg.HistoryDSN
+		if dsn == "" {
+			dsn = "data/history.db"
+		}
+		return newSQLChatHistoryStore("sqlite", dsn, sqliteHistoryTableSQL)
+	default:
+		return nil, fmt.Errorf("unknown history backend %q (want sqlite, mysql, or file)", cfg.HistoryBackend)
+	}
+}
+
+// migrateLegacyHistoryFile imports a pre-chunk5-1 newline-delimited
+// subreddit history file into store under legacyUserID, once. Migrated
+// entries carry no summary or model, since the flat file never recorded
+// them; the flat file itself is left in place.
+func migrateLegacyHistoryFile(store ChatHistoryStore, legacyFilePath string, legacyUserID int64, legacyUsername string) error {
+	if legacyFilePath == "" {
+		return nil
+	}
+
+	existing, err := store.Subreddits(legacyUserID)
+	if err != nil {
+		return fmt.Errorf("failed to check for existing history: %w", err)
+	}
+	if len(existing) > 0 {
+		// Already migrated, or the user has since built up real history.
+		return nil
+	}
+
+	data, err := os.ReadFile(legacyFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read legacy history file: %w", err)
+	}
+
+	var migrated int
+	for _, line := range strings.Split(string(data), "\n") {
+		subreddit := strings.TrimSpace(line)
+		if subreddit == "" {
+			continue
+		}
+		if err := store.Add(ChatHistoryEntry{
+			UserID:    legacyUserID,
+			Username:  legacyUsername,
+			Subreddit: subreddit,
+			Timestamp: time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to migrate entry for r/%s: %w", subreddit, err)
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		log.Printf("INFO: Migrated %d legacy history entries to user %d", migrated, legacyUserID)
+	}
+	return nil
+}
+
+// sqlChatHistoryStore is a database/sql-backed ChatHistoryStore shared by
+// the SQLite and MySQL backends, which differ only in driver name, DSN, and
+// CREATE TABLE syntax.
+type sqlChatHistoryStore struct {
+	db *sql.DB
+}
+
+const sqliteHistoryTableSQL = `
+CREATE TABLE IF NOT EXISTS chat_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	username TEXT NOT NULL,
+	subreddit TEXT NOT NULL,
+	summary TEXT NOT NULL,
+	model TEXT NOT NULL,
+	agent TEXT NOT NULL DEFAULT '',
+	timestamp DATETIME NOT NULL
+)`
+
+const mysqlHistoryTableSQL = `
+CREATE TABLE IF NOT EXISTS chat_history (
+	id BIGINT AUTO_INCREMENT PRIMARY KEY,
+	user_id BIGINT NOT NULL,
+	username VARCHAR(255) NOT NULL,
+	subreddit VARCHAR(255) NOT NULL,
+	summary MEDIUMTEXT NOT NULL,
+	model VARCHAR(255) NOT NULL,
+	agent VARCHAR(255) NOT NULL DEFAULT '',
+	timestamp DATETIME NOT NULL,
+	INDEX idx_chat_history_user_id (user_id)
+)`
+
+// newSQLChatHistoryStore opens dsn with driverName, creates the chat_history
+// table if it doesn't already exist, and returns a store backed by it.
+func newSQLChatHistoryStore(driverName, dsn, createTableSQL string) (*sqlChatHistoryStore, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s history database: %w", driverName, err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s history database: %w", driverName, err)
+	}
+
+	if _, err := db.Exec(createTableSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create chat_history table: %w", err)
+	}
+
+	return &sqlChatHistoryStore{db: db}, nil
+}
+
+func (s *sqlChatHistoryStore) Add(entry ChatHistoryEntry) error {
+	_, err := s.db.Exec(
+		`INSERT INTO chat_history (user_id, username, subreddit, summary, model, agent, timestamp) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.UserID, entry.Username, entry.Subreddit, entry.Summary, entry.Model, entry.Agent, entry.Timestamp,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert chat history entry: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlChatHistoryStore) Recent(userID int64, n int) ([]ChatHistoryEntry, error) {
+	rows, err := s.db.Query(
+		`SELECT user_id, username, subreddit, summary, model, agent, timestamp FROM chat_history WHERE user_id = ? ORDER BY timestamp DESC LIMIT ?`,
+		userID, n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent chat history: %w", err)
+	}
+	defer rows.Close()
+	return scanChatHistoryRows(rows)
+}
+
+func (s *sqlChatHistoryStore) Search(userID int64, query string) ([]ChatHistoryEntry, error) {
+	needle := "%" + query + "%"
+	rows, err := s.db.Query(
+		`SELECT user_id, username, subreddit, summary, model, agent, timestamp FROM chat_history
+		 WHERE user_id = ? AND (subreddit LIKE ? OR summary LIKE ?) ORDER BY timestamp DESC`,
+		userID, needle, needle,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search chat history: %w", err)
+	}
+	defer rows.Close()
+	return scanChatHistoryRows(rows)
+}
+
+func (s *sqlChatHistoryStore) Latest(userID int64, subreddit string) (*ChatHistoryEntry, error) {
+	row := s.db.QueryRow(
+		`SELECT user_id, username, subreddit, summary, model, agent, timestamp FROM chat_history
+		 WHERE user_id = ? AND subreddit = ? ORDER BY timestamp DESC LIMIT 1`,
+		userID, subreddit,
+	)
+
+	var entry ChatHistoryEntry
+	if err := row.Scan(&entry.UserID, &entry.Username, &entry.Subreddit, &entry.Summary, &entry.Model, &entry.Agent, &entry.Timestamp); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to look up latest chat history entry: %w", err)
+	}
+	return &entry, nil
+}
+
+func (s *sqlChatHistoryStore) Subreddits(userID int64) ([]string, error) {
+	rows, err := s.db.Query(
+		`SELECT subreddit, MAX(timestamp) AS last_seen FROM chat_history WHERE user_id = ? GROUP BY subreddit ORDER BY last_seen DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query distinct subreddits: %w", err)
+	}
+	defer rows.Close()
+
+	var subreddits []string
+	for rows.Next() {
+		var subreddit string
+		var lastSeen time.Time
+		if err := rows.Scan(&subreddit, &lastSeen); err != nil {
+			return nil, fmt.Errorf("failed to scan subreddit row: %w", err)
+		}
+		subreddits = append(subreddits, subreddit)
+	}
+	return subreddits, rows.Err()
+}
+
+func (s *sqlChatHistoryStore) Clear(userID int64) error {
+	if _, err := s.db.Exec(`DELETE FROM chat_history WHERE user_id = ?`, userID); err != nil {
+		return fmt.Errorf("failed to clear chat history: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlChatHistoryStore) Close() error {
+	return s.db.Close()
+}
+
+// scanChatHistoryRows drains rows into a slice of ChatHistoryEntry.
+func scanChatHistoryRows(rows *sql.Rows) ([]ChatHistoryEntry, error) {
+	var entries []ChatHistoryEntry
+	for rows.Next() {
+		var entry ChatHistoryEntry
+		if err := rows.Scan(&entry.UserID, &entry.Username, &entry.Subreddit, &entry.Summary, &entry.Model, &entry.Agent, &entry.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan chat history row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// fileChatHistoryStore is a JSON-lines-backed ChatHistoryStore, for
+// deployments that don't want to run a database. Each line is one
+// marshaled ChatHistoryEntry, appended in arrival order.
+type fileChatHistoryStore struct {
+	filePath string
+	mutex    sync.RWMutex
+	entries  []ChatHistoryEntry
+}
+
+// newFileChatHistoryStore creates a fileChatHistoryStore, loading any
+// existing entries from filePath.
+func newFileChatHistoryStore(filePath string) (*fileChatHistoryStore, error) {
+	store := &fileChatHistoryStore{filePath: filePath}
+	if err := store.load(); err != nil {
+		return nil, fmt.Errorf("failed to read chat history file: %w", err)
+	}
+	return store, nil
+}
+
+func (s *fileChatHistoryStore) load() error {
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var entries []ChatHistoryEntry
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var entry ChatHistoryEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return fmt.Errorf("failed to parse history line: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	s.mutex.Lock()
+	s.entries = entries
+	s.mutex.Unlock()
+	return nil
+}
+
+func (s *fileChatHistoryStore) Add(entry ChatHistoryEntry) error {
+	s.mutex.Lock()
+	s.entries = append(s.entries, entry)
+	snapshot := append([]ChatHistoryEntry(nil), s.entries...)
+	s.mutex.Unlock()
+
+	return s.persist(snapshot)
+}
+
+func (s *fileChatHistoryStore) persist(entries []ChatHistoryEntry) error {
+	var builder strings.Builder
+	for _, entry := range entries {
+		raw, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal history entry: %w", err)
+		}
+		builder.Write(raw)
+		builder.WriteByte('\n')
+	}
+	return os.WriteFile(s.filePath, []byte(builder.String()), 0644)
+}
+
+func (s *fileChatHistoryStore) Recent(userID int64, n int) ([]ChatHistoryEntry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var matched []ChatHistoryEntry
+	for i := len(s.entries) - 1; i >= 0 && len(matched) < n; i-- {
+		if s.entries[i].UserID == userID {
+			matched = append(matched, s.entries[i])
+		}
+	}
+	return matched, nil
+}
+
+func (s *fileChatHistoryStore) Search(userID int64, query string) ([]ChatHistoryEntry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	needle := strings.ToLower(query)
+	var matched []ChatHistoryEntry
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if entry.UserID != userID {
+			continue
+		}
+		if strings.Contains(strings.ToLower(entry.Subreddit), needle) || strings.Contains(strings.ToLower(entry.Summary), needle) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched, nil
+}
+
+func (s *fileChatHistoryStore) Latest(userID int64, subreddit string) (*ChatHistoryEntry, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if entry.UserID == userID && strings.EqualFold(entry.Subreddit, subreddit) {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *fileChatHistoryStore) Subreddits(userID int64) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var subreddits []string
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		entry := s.entries[i]
+		if entry.UserID != userID {
+			continue
+		}
+		key := strings.ToLower(entry.Subreddit)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		subreddits = append(subreddits, entry.Subreddit)
+	}
+	return subreddits, nil
+}
+
+func (s *fileChatHistoryStore) Clear(userID int64) error {
+	s.mutex.Lock()
+	kept := s.entries[:0:0]
+	for _, entry := range s.entries {
+		if entry.UserID != userID {
+			kept = append(kept, entry)
+		}
+	}
+	s.entries = kept
+	snapshot := append([]ChatHistoryEntry(nil), s.entries...)
+	s.mutex.Unlock()
+
+	return s.persist(snapshot)
+}
+
+func (s *fileChatHistoryStore) Close() error {
+	return nil
+}
+
+// historyWriter serializes ChatHistoryEntry writes through a single
+// goroutine reading off a buffered channel, so a bursty handleMessage
+// never blocks on the store and a shutdown can drain the channel instead
+// of racing an ad-hoc goroutine mid-write. Reads (Recent, Search, Latest,
+// Subreddits, Clear) still go straight to the underlying store, since only
+// writes need serializing.
+type historyWriter struct {
+	store   ChatHistoryStore
+	entries chan ChatHistoryEntry
+	done    chan struct{}
+}
+
+// newHistoryWriter starts the writer goroutine and returns a handle to it.
+// buffer bounds how many unwritten entries Enqueue can queue up before it
+// blocks the caller.
+func newHistoryWriter(store ChatHistoryStore, buffer int) *historyWriter {
+	w := &historyWriter{
+		store:   store,
+		entries: make(chan ChatHistoryEntry, buffer),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+func (w *historyWriter) run() {
+	defer close(w.done)
+	for entry := range w.entries {
+		if err := w.store.Add(entry); err != nil {
+			log.Printf("ERROR: Failed to persist chat history entry for r/%s: %v", entry.Subreddit, err)
+		}
+	}
+}
+
+// Enqueue hands entry to the writer goroutine. It returns as soon as the
+// entry is queued, not once it's durably written; failures are logged by
+// the writer goroutine rather than returned here.
+func (w *historyWriter) Enqueue(entry ChatHistoryEntry) {
+	w.entries <- entry
+}
+
+// Stop closes the entry channel and waits for the writer goroutine to
+// drain whatever was already queued, bounded by ctx, so a shutdown can't
+// truncate a write that was already in flight.
+func (w *historyWriter) Stop(ctx context.Context) error {
+	close(w.entries)
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("history writer did not drain in time: %w", ctx.Err())
+	}
+}
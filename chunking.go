@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// modelMaxContextTokens gives the assumed context-window budget (in tokens)
+// for models we know about. Models not listed fall back to
+// defaultMaxContextTokens.
+var modelMaxContextTokens = map[string]int{
+	"gpt-5-mini": 128000,
+	"gpt-5":      128000,
+	"llama3":     8192,
+}
+
+// defaultMaxContextTokens is used for models with no entry in
+// modelMaxContextTokens.
+const defaultMaxContextTokens = 8192
+
+// chunkReserveTokens is subtracted from a model's context budget to leave
+// room for the prompt template, system overhead, and the response itself.
+const chunkReserveTokens = 1500
+
+// postSeparator is how subredditDataWithProgress joins individual posts;
+// chunkPostsText splits on it so a chunk never cuts a post in half.
+const postSeparator = "\n---\n\n"
+
+// chunkSummaryProgressFunc reports progress while summarizePostsMapReduce
+// works through the map and reduce stages. stage is "mapping" or "reducing";
+// current/total describe position within that stage (e.g. chunk 3 of 7).
+type chunkSummaryProgressFunc func(stage string, current, total int)
+
+const mapChunkPromptTemplate = `Summarize part %d of %d of the Reddit posts and discussions from r/%s below. Focus on the main themes, notable comments, and overall sentiment in this part only — you won't see the rest of the discussion.
+
+Give a concise bullet-point summary; skip the TRENDING TOPICS/COMMUNITY PULSE/HOT TAKES formatting, that happens in a later step once all parts are combined.
+
+Posts to analyze:
+
+%s`
+
+const reducePromptTemplate = `You previously summarized r/%s's top posts and discussions in %d separate parts because they were too long for a single pass. Combine these partial summaries into one engaging overview.
+
+Focus on:
+- Main themes and topics across all parts; merge similar topics together
+- Key points and interesting insights from across the parts
+- Notable trends, patterns, or controversies
+- Overall community sentiment and mood
+
+Format your response with:
+- 📊 TRENDING TOPICS: List the main themes with emoji indicators
+- 💬 COMMUNITY PULSE: Describe the overall sentiment and notable discussions
+- 🔥 HOT TAKES: Highlight the most interesting or controversial opinions
+
+Rules:
+- Be conversational and engaging, like you're telling a friend about what's happening on Reddit
+- Use appropriate emojis to make the summary more visually appealing
+- Don't reference "parts" or "chunks" — write as if summarizing the whole discussion at once
+- Keep your tone friendly and slightly humorous where appropriate
+
+Partial summaries to combine:
+
+%s`
+
+// maxContextTokensForModel returns the assumed context window for model.
+func maxContextTokensForModel(model string) int {
+	if tokens, ok := modelMaxContextTokens[model]; ok {
+		return tokens
+	}
+	return defaultMaxContextTokens
+}
+
+// estimateTokens approximates the number of tokens in text using the common
+// "~4 characters per token" heuristic for English text. It's a deliberately
+// cheap fallback for deciding whether to chunk, not for billing.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// chunkPostsText splits text (the builder output from subredditDataWithProgress,
+// posts joined by postSeparator) into chunks that each fit within maxTokens,
+// never splitting a single post across two chunks. If a single post alone
+// exceeds maxTokens it becomes its own oversized chunk rather than being
+// truncated.
+func chunkPostsText(text string, maxTokens int) []string {
+	posts := strings.Split(text, postSeparator)
+
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentTokens = 0
+		}
+	}
+
+	for _, post := range posts {
+		if strings.TrimSpace(post) == "" {
+			continue
+		}
+		postTokens := estimateTokens(post)
+		if currentTokens > 0 && currentTokens+postTokens > maxTokens {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteString(postSeparator)
+		}
+		current.WriteString(post)
+		currentTokens += postTokens
+	}
+	flush()
+
+	return chunks
+}
+
+// formatChunkResponse extracts the raw text from a partial-summary (map
+// stage) response, without the Markdown/header formatting formatResponse
+// applies to a final summary.
+func formatChunkResponse(response *ChatCompletionResponse) (string, error) {
+	if response == nil || len(response.Choices) == 0 {
+		return "", fmt.Errorf("empty content in response")
+	}
+	text := response.Choices[0].Message.Content
+	if text == "" {
+		return "", fmt.Errorf("empty text in response content")
+	}
+	return text, nil
+}
+
+// summarizePostsMapReduce summarizes text in two passes when it's too large
+// for model's context window: a "map" pass summarizes each chunk
+// concurrently (bounded by a small semaphore, on top of the existing
+// openaiLimiter rate limiting), then a "reduce" pass combines the partial
+// summaries into the final TRENDING TOPICS/COMMUNITY PULSE/HOT TAKES format.
+// onProgress, if non-nil, is called as each chunk is mapped and again for
+// the reduce step. It returns both the rendered Markdown and the structured
+// SummaryResult behind it, so callers with richer clients (e.g. the web UI)
+// aren't limited to the rendered string.
+func summarizePostsMapReduce(ctx context.Context, subreddit, text, model string, onProgress chunkSummaryProgressFunc) (string, *SummaryResult, error) {
+	maxTokens := maxContextTokensForModel(model) - chunkReserveTokens
+	if maxTokens < 1 {
+		maxTokens = defaultMaxContextTokens
+	}
+
+	chunks := chunkPostsText(text, maxTokens)
+	if len(chunks) == 0 {
+		return "", nil, fmt.Errorf("no posts to summarize")
+	}
+
+	log.Printf("INFO: r/%s summary exceeds the %d-token budget for model %s, splitting into %d chunks", subreddit, maxTokens, model, len(chunks))
+
+	partials := make([]string, len(chunks))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 3)
+	errs := make(chan error, len(chunks))
+
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk string) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			if onProgress != nil {
+				onProgress("mapping", i+1, len(chunks))
+			}
+
+			request := ChatCompletionRequest{
+				Model: model,
+				Messages: []OpenAIMessage{
+					{Role: "user", Content: fmt.Sprintf(mapChunkPromptTemplate, i+1, len(chunks), subreddit, chunk)},
+				},
+			}
+			response, err := makeOpenAIAPICall(ctx, request, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey)
+			if err != nil {
+				errs <- fmt.Errorf("failed to summarize chunk %d/%d: %w", i+1, len(chunks), err)
+				return
+			}
+			partial, err := formatChunkResponse(response)
+			if err != nil {
+				errs <- fmt.Errorf("failed to parse summary for chunk %d/%d: %w", i+1, len(chunks), err)
+				return
+			}
+			partials[i] = partial
+		}(i, chunk)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if onProgress != nil {
+		onProgress("reducing", 1, 1)
+	}
+
+	reduceRequest := ChatCompletionRequest{
+		Model: model,
+		Messages: []OpenAIMessage{
+			{Role: "user", Content: fmt.Sprintf(reducePromptTemplate, subreddit, len(chunks), strings.Join(partials, "\n\n---\n\n"))},
+		},
+		ResponseFormat: summaryResponseFormat,
+	}
+	response, err := makeOpenAIAPICall(ctx, reduceRequest, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to reduce chunk summaries: %w", err)
+	}
+	return formatStructuredResponse(response)
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -26,6 +27,7 @@ type Config struct {
 	RedditPostLimit          int
 	RedditCommentLimit       int
 	RedditTimeFrame          string
+	RedditDefaultSort        string
 	RedditRequestsPerSecond  int
 	RedditBurstSize          int
 	RedditTokenExpiryBuffer  time.Duration
@@ -36,6 +38,7 @@ type Config struct {
 	RedditPublicURL          string
 	RedditClientID           string
 	RedditClientSecret       string
+	TokenStore               string
 
 	// Discord Bot settings
 	DiscordMessageSplitLength int
@@ -43,9 +46,90 @@ type Config struct {
 	SessionFilePath           string
 	HistoryInitCapacity       int
 	HistoryDisplayLimit       int
+	MaxWatchersPerUser        int
+
+	// Web server session settings. The cookie only holds a session ID;
+	// History and model choice live server-side in SessionStore, backed by
+	// SessionBackend ("memory", the default, or "redis").
+	SessionBackend  string
+	SessionRedisURL string
+
+	// /analyze protection: a per-client token bucket plus a global cap on
+	// concurrent requests in flight, so one user (or a burst of users) can't
+	// run up unbounded Reddit + OpenAI API usage. The token bucket is keyed
+	// by client IP rather than session, and MaxRateLimiterEntries bounds how
+	// many distinct IPs' buckets are kept in memory at once (LRU-evicted).
+	AnalyzeRequestsPerMinute int
+	AnalyzeBurstSize         int
+	MaxRequestsInFlight      int
+	MaxRateLimiterEntries    int
+
+	// AnalyzeCacheTTL controls how long a rendered /analyze result is reused
+	// for the same (subreddit, model) before it's refetched, and how long a
+	// "?refresh=1" bypass is honored before the next request serves cache
+	// again.
+	AnalyzeCacheTTL time.Duration
+
+	// Observability settings. OTelExporterOTLPEndpoint enables OpenTelemetry
+	// tracing (OTLP/gRPC) when set; MetricsEnabled exposes Prometheus
+	// metrics at /metrics. LogFormat ("text", the default, or "json") and
+	// LogLevel ("debug", "info", the default, "warn", or "error") configure
+	// AppLogger (see logging.go).
+	OTelExporterOTLPEndpoint string
+	MetricsEnabled           bool
+	LogFormat                string
+	LogLevel                 string
+
+	// TLS settings for the web server. Set TLSCertFile/TLSKeyFile to serve a
+	// fixed certificate, or AutocertDomains to obtain one from Let's Encrypt
+	// instead. ClientCAFile turns on mutual TLS, requiring (or requesting,
+	// per ClientAuthType) a client certificate signed by that CA.
+	TLSCertFile      string
+	TLSKeyFile       string
+	AutocertDomains  []string
+	AutocertCacheDir string
+	ClientCAFile     string
+	ClientAuthType   string
+
+	// LLM backend settings. subtrends can summarize via hosted OpenAI or any
+	// OpenAI-compatible local server (Ollama, LocalAI, llama.cpp server,
+	// vLLM), selected via LLMProvider.
+	LLMProvider             string
+	OpenAIAPIEndpoint       string
+	OpenAIAPIKey            string
+	OpenAIRequestTimeout    time.Duration
+	OpenAIRequestsPerMinute int
+	OpenAIBurstSize         int
+	OpenAIModels            []string
+	LocalLLMEndpoint        string
+	LocalLLMAPIKey          string
+	LocalLLMModels          []string
+
+	// Cache settings. Reddit fetches and OpenAI summaries are cached for
+	// CacheTTL, in-memory (an LRU bounded by CacheCapacity) unless RedisURL
+	// is set, in which case Redis backs the cache instead.
+	RedisURL      string
+	CacheTTL      time.Duration
+	CacheCapacity int
 
 	// Application settings
 	ShutdownTimeout time.Duration
+
+	// Telegram chat history settings. HistoryBackend selects the
+	// ChatHistoryStore implementation ("sqlite", the default; "mysql"; or
+	// "file"); HistoryDSN is that backend's connection string or file path,
+	// defaulting per-backend when empty.
+	HistoryBackend string
+	HistoryDSN     string
+
+	// AgentsFilePath points at a YAML or JSON file defining the Telegram
+	// bot's named Agent profiles (see agents.go). Empty, missing, or
+	// malformed falls back to the built-in default agents.
+	AgentsFilePath string
+
+	// TelegramWatcherFilePath is where the Telegram bot's /watch entries are
+	// persisted (see telegram_watchers.go).
+	TelegramWatcherFilePath string
 }
 
 // AppConfig holds the application's loaded configuration.
@@ -71,6 +155,7 @@ func LoadConfig() {
 		RedditPostLimit:          getEnvAsInt("REDDIT_POST_LIMIT", 7),
 		RedditCommentLimit:       getEnvAsInt("REDDIT_COMMENT_LIMIT", 7),
 		RedditTimeFrame:          getEnv("REDDIT_TIMEFRAME", "day"),
+		RedditDefaultSort:        getEnv("REDDIT_DEFAULT_SORT", "top"),
 		RedditRequestsPerSecond:  getEnvAsInt("REDDIT_REQUESTS_PER_SECOND", 1),
 		RedditBurstSize:          getEnvAsInt("REDDIT_BURST_SIZE", 5),
 		RedditTokenExpiryBuffer:  getEnvAsDuration("REDDIT_TOKEN_EXPIRY_BUFFER", 5*time.Minute),
@@ -81,6 +166,7 @@ func LoadConfig() {
 		RedditPublicURL:          getEnv("REDDIT_PUBLIC_URL", "https://reddit.com"),
 		RedditClientID:           getEnv("REDDIT_CLIENT_ID", ""),
 		RedditClientSecret:       getEnv("REDDIT_CLIENT_SECRET", ""),
+		TokenStore:               getEnv("TOKEN_STORE", "file"),
 
 		// Discord Bot
 		SessionFilePath:           getEnv("SESSION_FILE_PATH", "data/sessions.json"),
@@ -88,9 +174,62 @@ func LoadConfig() {
 		HistoryDisplayLimit:       getEnvAsInt("HISTORY_DISPLAY_LIMIT", 25),
 		DiscordMessageSplitLength: getEnvAsInt("DISCORD_MESSAGE_SPLIT_LENGTH", 1900),
 		LegacyCommandPrefix:       getEnv("LEGACY_COMMAND_PREFIX", "!trend "),
+		MaxWatchersPerUser:        getEnvAsInt("MAX_WATCHERS_PER_USER", 5),
+
+		// Web session
+		SessionBackend:  getEnv("SESSION_BACKEND", "memory"),
+		SessionRedisURL: getEnv("SESSION_REDIS_URL", ""),
+
+		// /analyze protection
+		AnalyzeRequestsPerMinute: getEnvAsInt("ANALYZE_REQUESTS_PER_MINUTE", 10),
+		AnalyzeBurstSize:         getEnvAsInt("ANALYZE_BURST_SIZE", 3),
+		MaxRequestsInFlight:      getEnvAsInt("MAX_REQUESTS_IN_FLIGHT", 10),
+		MaxRateLimiterEntries:    getEnvAsInt("MAX_RATE_LIMITER_ENTRIES", 10000),
+		AnalyzeCacheTTL:          getEnvAsDuration("ANALYZE_CACHE_TTL", 15*time.Minute),
+
+		// Observability
+		OTelExporterOTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		MetricsEnabled:           getEnvAsBool("METRICS_ENABLED", true),
+		LogFormat:                getEnv("LOG_FORMAT", "text"),
+		LogLevel:                 getEnv("LOG_LEVEL", "info"),
+
+		// TLS
+		TLSCertFile:      getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:       getEnv("TLS_KEY_FILE", ""),
+		AutocertDomains:  getEnvAsStringSlice("AUTOCERT_DOMAINS", []string{}),
+		AutocertCacheDir: getEnv("AUTOCERT_CACHE_DIR", "data/autocert-cache"),
+		ClientCAFile:     getEnv("CLIENT_CA_FILE", ""),
+		ClientAuthType:   getEnv("CLIENT_AUTH_TYPE", "RequireAndVerifyClientCert"),
+
+		// LLM backend
+		LLMProvider:             getEnv("LLM_PROVIDER", "openai"),
+		OpenAIAPIEndpoint:       getEnv("OPENAI_API_ENDPOINT", "https://api.openai.com/v1/chat/completions"),
+		OpenAIAPIKey:            getEnv("OPENAI_API_KEY", ""),
+		OpenAIRequestTimeout:    getEnvAsDuration("OPENAI_REQUEST_TIMEOUT", 45*time.Second),
+		OpenAIRequestsPerMinute: getEnvAsInt("OPENAI_REQUESTS_PER_MINUTE", 10),
+		OpenAIBurstSize:         getEnvAsInt("OPENAI_BURST_SIZE", 3),
+		OpenAIModels:            getEnvAsStringSlice("OPENAI_MODELS", []string{"gpt-5-mini", "gpt-5"}),
+		LocalLLMEndpoint:        getEnv("LOCAL_LLM_ENDPOINT", "http://localhost:11434/v1/chat/completions"),
+		LocalLLMAPIKey:          getEnv("LOCAL_LLM_API_KEY", ""),
+		LocalLLMModels:          getEnvAsStringSlice("LOCAL_LLM_MODELS", []string{"llama3"}),
+
+		// Cache
+		RedisURL:      getEnv("REDIS_URL", ""),
+		CacheTTL:      getEnvAsDuration("CACHE_TTL", 10*time.Minute),
+		CacheCapacity: getEnvAsInt("CACHE_CAPACITY", 500),
 
 		// Application
 		ShutdownTimeout: getEnvAsDuration("SHUTDOWN_TIMEOUT", 5*time.Second),
+
+		// Telegram chat history
+		HistoryBackend: getEnv("HISTORY_BACKEND", "sqlite"),
+		HistoryDSN:     getEnv("HISTORY_DSN", ""),
+
+		// Telegram agents
+		AgentsFilePath: getEnv("AGENTS_FILE_PATH", ""),
+
+		// Telegram watchers
+		TelegramWatcherFilePath: getEnv("TELEGRAM_WATCHER_FILE_PATH", "data/telegram_watchers.json"),
 	}
 }
 
@@ -119,6 +258,14 @@ func getEnvAsFloat64(key string, fallback float64) float64 {
 	return fallback
 }
 
+func getEnvAsBool(key string, fallback bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return fallback
+}
+
 func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
 	valueStr := getEnv(key, "")
 	if value, err := time.ParseDuration(valueStr); err == nil {
@@ -126,3 +273,24 @@ func getEnvAsDuration(key string, fallback time.Duration) time.Duration {
 	}
 	return fallback
 }
+
+// getEnvAsStringSlice reads key as a comma-separated list, trimming
+// whitespace around each entry. Returns fallback if key isn't set.
+func getEnvAsStringSlice(key string, fallback []string) []string {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return fallback
+	}
+
+	parts := strings.Split(valueStr, ",")
+	models := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			models = append(models, trimmed)
+		}
+	}
+	if len(models) == 0 {
+		return fallback
+	}
+	return models
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// guildConfigFilePath is where per-guild command configuration is persisted.
+var guildConfigFilePath = filepath.Join("data", "guild_configs.json")
+
+// GuildCommandConfig holds one guild's channel allow-list and per-command
+// cooldowns for the restrictable commands (/trend, /model, /history, /clear).
+type GuildCommandConfig struct {
+	AllowedChannels map[string]struct{}
+	Cooldowns       map[string]time.Duration
+	usersOnCooldown map[string]map[string]time.Time
+	mutex           sync.RWMutex
+}
+
+// CommandConfig manages per-guild command configuration, guarding concurrent
+// access from interactionCreate's middleware and the /config command.
+type CommandConfig struct {
+	mutex  sync.RWMutex
+	guilds map[string]*GuildCommandConfig
+}
+
+// restrictableCommands lists the slash commands that /config can gate by
+// channel and cooldown.
+var restrictableCommands = map[string]bool{
+	"trend":   true,
+	"model":   true,
+	"history": true,
+	"clear":   true,
+}
+
+// NewCommandConfig creates a CommandConfig, loading any persisted
+// configuration from data/guild_configs.json.
+func NewCommandConfig() *CommandConfig {
+	cc := &CommandConfig{guilds: make(map[string]*GuildCommandConfig)}
+	cc.load()
+	return cc
+}
+
+// guildConfig returns (creating if necessary) the config for a guild.
+func (cc *CommandConfig) guildConfig(guildID string) *GuildCommandConfig {
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+
+	gc, ok := cc.guilds[guildID]
+	if !ok {
+		gc = &GuildCommandConfig{
+			AllowedChannels: make(map[string]struct{}),
+			Cooldowns:       make(map[string]time.Duration),
+			usersOnCooldown: make(map[string]map[string]time.Time),
+		}
+		cc.guilds[guildID] = gc
+	}
+	return gc
+}
+
+// AllowChannel adds channelID to the guild's allow-list. An empty allow-list
+// means all channels are permitted.
+func (cc *CommandConfig) AllowChannel(guildID, channelID string) {
+	gc := cc.guildConfig(guildID)
+	gc.mutex.Lock()
+	gc.AllowedChannels[channelID] = struct{}{}
+	gc.mutex.Unlock()
+	cc.save()
+}
+
+// DenyChannel removes channelID from the guild's allow-list.
+func (cc *CommandConfig) DenyChannel(guildID, channelID string) {
+	gc := cc.guildConfig(guildID)
+	gc.mutex.Lock()
+	delete(gc.AllowedChannels, channelID)
+	gc.mutex.Unlock()
+	cc.save()
+}
+
+// SetCooldown sets the per-user cooldown duration for a command in a guild.
+func (cc *CommandConfig) SetCooldown(guildID, command string, cooldown time.Duration) {
+	gc := cc.guildConfig(guildID)
+	gc.mutex.Lock()
+	gc.Cooldowns[command] = cooldown
+	gc.mutex.Unlock()
+	cc.save()
+}
+
+// IsChannelAllowed reports whether channelID may be used for commands in the
+// guild. An empty allow-list permits every channel.
+func (cc *CommandConfig) IsChannelAllowed(guildID, channelID string) bool {
+	gc := cc.guildConfig(guildID)
+	gc.mutex.RLock()
+	defer gc.mutex.RUnlock()
+
+	if len(gc.AllowedChannels) == 0 {
+		return true
+	}
+	_, ok := gc.AllowedChannels[channelID]
+	return ok
+}
+
+// CheckCooldown reports whether userID may run command in the guild right
+// now, and if not, how long until they can. A successful check starts the
+// cooldown timer for the next invocation.
+func (cc *CommandConfig) CheckCooldown(guildID, command, userID string) (bool, time.Duration) {
+	gc := cc.guildConfig(guildID)
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+
+	cooldown, configured := gc.Cooldowns[command]
+	if !configured || cooldown <= 0 {
+		return true, 0
+	}
+
+	perUser, ok := gc.usersOnCooldown[command]
+	if !ok {
+		perUser = make(map[string]time.Time)
+		gc.usersOnCooldown[command] = perUser
+	}
+
+	if last, ok := perUser[userID]; ok {
+		if remaining := cooldown - time.Since(last); remaining > 0 {
+			return false, remaining
+		}
+	}
+
+	perUser[userID] = time.Now()
+	return true, 0
+}
+
+// Show renders a human-readable summary of a guild's configuration.
+func (cc *CommandConfig) Show(guildID string) string {
+	gc := cc.guildConfig(guildID)
+	gc.mutex.RLock()
+	defer gc.mutex.RUnlock()
+
+	msg := "**Allowed channels:** "
+	if len(gc.AllowedChannels) == 0 {
+		msg += "all channels\n"
+	} else {
+		msg += "\n"
+		for channelID := range gc.AllowedChannels {
+			msg += fmt.Sprintf("• <#%s>\n", channelID)
+		}
+	}
+
+	msg += "**Cooldowns:**\n"
+	if len(gc.Cooldowns) == 0 {
+		msg += "• none configured\n"
+	} else {
+		for command, cooldown := range gc.Cooldowns {
+			msg += fmt.Sprintf("• `/%s`: %s\n", command, cooldown)
+		}
+	}
+
+	return msg
+}
+
+// persistedGuildConfig is the on-disk representation of a GuildCommandConfig.
+type persistedGuildConfig struct {
+	AllowedChannels map[string]struct{} `json:"allowed_channels"`
+	Cooldowns       map[string]time.Duration `json:"cooldowns"`
+}
+
+// load reads data/guild_configs.json into the in-memory guild map.
+func (cc *CommandConfig) load() {
+	var persisted map[string]persistedGuildConfig
+	if err := ReadJSONFile(guildConfigFilePath, &persisted); err != nil {
+		log.Printf("WARNING: Failed to read guild configs: %v", err)
+		return
+	}
+
+	cc.mutex.Lock()
+	defer cc.mutex.Unlock()
+	for guildID, pgc := range persisted {
+		cc.guilds[guildID] = &GuildCommandConfig{
+			AllowedChannels: pgc.AllowedChannels,
+			Cooldowns:       pgc.Cooldowns,
+			usersOnCooldown: make(map[string]map[string]time.Time),
+		}
+	}
+}
+
+// save writes the current guild configuration to data/guild_configs.json.
+func (cc *CommandConfig) save() {
+	cc.mutex.RLock()
+	persisted := make(map[string]persistedGuildConfig, len(cc.guilds))
+	for guildID, gc := range cc.guilds {
+		gc.mutex.RLock()
+		persisted[guildID] = persistedGuildConfig{
+			AllowedChannels: gc.AllowedChannels,
+			Cooldowns:       gc.Cooldowns,
+		}
+		gc.mutex.RUnlock()
+	}
+	cc.mutex.RUnlock()
+
+	if err := WriteJSONFile(guildConfigFilePath, persisted); err != nil {
+		log.Printf("ERROR: Failed to persist guild configs: %v", err)
+	}
+}
+
+// configCommandDefaultPermission restricts /config to members with the
+// Manage Server permission by default. Discord still lets a guild admin
+// override this in Integrations settings, so handleConfigSlashCommand also
+// checks the invoker's permissions itself rather than relying on this alone.
+var configCommandDefaultPermission int64 = discordgo.PermissionManageServer
+
+// configCommand is the /config ApplicationCommand definition registered
+// alongside /trend, /model, /history, and /clear.
+var configCommand = &discordgo.ApplicationCommand{
+	Name:                     "config",
+	Description:              "Configure allowed channels and cooldowns for this server",
+	DefaultMemberPermissions: &configCommandDefaultPermission,
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "allow-channel",
+			Description: "Allow commands in this channel",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "deny-channel",
+			Description: "Disallow commands in this channel",
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "set-cooldown",
+			Description: "Set a per-user cooldown for a command",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "command",
+					Description: "Command to limit (trend, model, history, clear)",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "seconds",
+					Description: "Cooldown duration in seconds",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionSubCommand,
+			Name:        "show",
+			Description: "Show the current configuration for this server",
+		},
+	},
+}
+
+// handleConfigSlashCommand handles the /config command and its subcommands.
+func (bot *DiscordBot) handleConfigSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if bot.commandConfig == nil {
+		bot.respondError(s, i, "Command configuration is not available")
+		return
+	}
+
+	if i.Member == nil || i.Member.Permissions&discordgo.PermissionManageServer == 0 {
+		bot.respondEphemeral(s, i, "❌ You need the Manage Server permission to use `/config`")
+		return
+	}
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		bot.respondError(s, i, "Please provide a subcommand")
+		return
+	}
+
+	sub := options[0]
+	guildID := i.GuildID
+	var content string
+
+	switch sub.Name {
+	case "allow-channel":
+		bot.commandConfig.AllowChannel(guildID, i.ChannelID)
+		content = fmt.Sprintf("✅ Commands are now allowed in <#%s>", i.ChannelID)
+	case "deny-channel":
+		bot.commandConfig.DenyChannel(guildID, i.ChannelID)
+		content = fmt.Sprintf("✅ Commands are no longer allowed in <#%s>", i.ChannelID)
+	case "set-cooldown":
+		subOptions := sub.Options
+		if len(subOptions) < 2 {
+			bot.respondError(s, i, "Please provide a command and a duration")
+			return
+		}
+		command := subOptions[0].StringValue()
+		seconds := subOptions[1].IntValue()
+		bot.commandConfig.SetCooldown(guildID, command, time.Duration(seconds)*time.Second)
+		content = fmt.Sprintf("✅ `/%s` cooldown set to %ds", command, seconds)
+	case "show":
+		content = bot.commandConfig.Show(guildID)
+	default:
+		bot.respondError(s, i, "Unknown subcommand")
+		return
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to config interaction: %v", err)
+	}
+}
+
+// enforceCommandConfig checks the channel allow-list and cooldown for a
+// restrictable command, responding ephemerally and returning false if the
+// command should not proceed.
+func (bot *DiscordBot) enforceCommandConfig(s *discordgo.Session, i *discordgo.InteractionCreate, command, userID string) bool {
+	if bot.commandConfig == nil || !restrictableCommands[command] {
+		return true
+	}
+
+	if !bot.commandConfig.IsChannelAllowed(i.GuildID, i.ChannelID) {
+		bot.respondEphemeral(s, i, fmt.Sprintf("❌ `/%s` is not allowed in this channel", command))
+		return false
+	}
+
+	if ok, remaining := bot.commandConfig.CheckCooldown(i.GuildID, command, userID); !ok {
+		bot.respondEphemeral(s, i, fmt.Sprintf("⏳ On cooldown, try again in %ds", int(remaining.Seconds())+1))
+		return false
+	}
+
+	return true
+}
+
+// respondEphemeral sends an ephemeral interaction response.
+func (bot *DiscordBot) respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding ephemerally: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// HistoryStore persists each user's subreddit search history in isolation,
+// so members of a shared guild can't see or clear one another's history.
+type HistoryStore interface {
+	Add(userID, subreddit string) error
+	List(userID string) ([]string, error)
+	Clear(userID string) error
+	Recent(userID string, n int) ([]string, error)
+}
+
+// fileHistoryStore is a JSON-file-backed HistoryStore, keyed by user ID. It
+// follows the same ReadJSONFile/WriteJSONFile persistence convention as
+// Discord user sessions rather than pulling in a SQLite/BoltDB driver.
+type fileHistoryStore struct {
+	filePath string
+	mutex    sync.RWMutex
+	byUser   map[string][]string
+}
+
+// NewFileHistoryStore creates a fileHistoryStore, loading any existing data
+// from filePath and migrating a legacy flat-file history (one subreddit per
+// line, shared across all users) into userID's bucket if present.
+func NewFileHistoryStore(filePath, legacyFlatFilePath, legacyUserID string) (*fileHistoryStore, error) {
+	store := &fileHistoryStore{
+		filePath: filePath,
+		byUser:   make(map[string][]string),
+	}
+
+	if err := ReadJSONFile(filePath, &store.byUser); err != nil {
+		return nil, fmt.Errorf("failed to read history store: %w", err)
+	}
+	if store.byUser == nil {
+		store.byUser = make(map[string][]string)
+	}
+
+	if err := store.migrateLegacyFile(legacyFlatFilePath, legacyUserID); err != nil {
+		log.Printf("WARNING: Failed to migrate legacy history file: %v", err)
+	}
+
+	return store, nil
+}
+
+// migrateLegacyFile imports a pre-existing newline-delimited history file
+// into legacyUserID's bucket, once. The flat file is left in place but its
+// contents are only re-imported if the user has no history yet.
+func (s *fileHistoryStore) migrateLegacyFile(legacyFlatFilePath, legacyUserID string) error {
+	if legacyFlatFilePath == "" || legacyUserID == "" {
+		return nil
+	}
+
+	s.mutex.RLock()
+	_, alreadyMigrated := s.byUser[legacyUserID]
+	s.mutex.RUnlock()
+	if alreadyMigrated {
+		return nil
+	}
+
+	data, err := os.ReadFile(legacyFlatFilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var subreddits []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			subreddits = append(subreddits, line)
+		}
+	}
+	if len(subreddits) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	s.byUser[legacyUserID] = subreddits
+	s.mutex.Unlock()
+
+	log.Printf("INFO: Migrated %d legacy history entries to user %s", len(subreddits), legacyUserID)
+	return s.persist()
+}
+
+// Add appends subreddit to userID's history if it's not already present.
+func (s *fileHistoryStore) Add(userID, subreddit string) error {
+	s.mutex.Lock()
+	for _, existing := range s.byUser[userID] {
+		if existing == subreddit {
+			s.mutex.Unlock()
+			return nil
+		}
+	}
+	s.byUser[userID] = append(s.byUser[userID], subreddit)
+	s.mutex.Unlock()
+
+	return s.persist()
+}
+
+// List returns userID's full history.
+func (s *fileHistoryStore) List(userID string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return append([]string(nil), s.byUser[userID]...), nil
+}
+
+// Clear removes all of userID's history.
+func (s *fileHistoryStore) Clear(userID string) error {
+	s.mutex.Lock()
+	delete(s.byUser, userID)
+	s.mutex.Unlock()
+	return s.persist()
+}
+
+// Recent returns the last n entries of userID's history.
+func (s *fileHistoryStore) Recent(userID string, n int) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	history := s.byUser[userID]
+	if len(history) <= n {
+		return append([]string(nil), history...), nil
+	}
+	return append([]string(nil), history[len(history)-n:]...), nil
+}
+
+// persist writes the full user history map to disk.
+func (s *fileHistoryStore) persist() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return WriteJSONFile(s.filePath, s.byUser)
+}
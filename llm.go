@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Token is a single streamed fragment of an LLM response. A Token with a
+// non-nil Err is always the last one sent on the channel; callers should
+// treat it as fatal and fall back to Summarize.
+type Token struct {
+	Text string
+	Err  error
+}
+
+// LLMBackend abstracts over the chat-completion providers subtrends can
+// summarize with: hosted OpenAI, or any local server speaking the same
+// OpenAI-compatible API shape (Ollama, LocalAI, llama.cpp server, vLLM).
+// This lets self-hosters run subtrends fully offline against e.g. Ollama on
+// localhost:11434 without an OpenAI key.
+type LLMBackend interface {
+	// Summarize returns the full completion for prompt in one call.
+	Summarize(ctx context.Context, model, prompt string) (string, error)
+	// SummarizeStream streams the completion token-by-token on the returned
+	// channel, which is closed when the stream ends.
+	SummarizeStream(ctx context.Context, model, prompt string) <-chan Token
+}
+
+// newLLMBackend builds the LLMBackend selected by cfg.LLMProvider ("openai",
+// the default, or "local" for any OpenAI-compatible local server).
+func newLLMBackend(cfg *Config) (LLMBackend, error) {
+	switch cfg.LLMProvider {
+	case "", "openai":
+		return &openAICompatibleBackend{endpoint: cfg.OpenAIAPIEndpoint, apiKey: cfg.OpenAIAPIKey}, nil
+	case "local", "ollama", "localai":
+		return &openAICompatibleBackend{endpoint: cfg.LocalLLMEndpoint, apiKey: cfg.LocalLLMAPIKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown LLM_PROVIDER %q", cfg.LLMProvider)
+	}
+}
+
+// openAICompatibleBackend implements LLMBackend against any server speaking
+// the OpenAI chat-completions API shape.
+type openAICompatibleBackend struct {
+	endpoint string
+	apiKey   string
+}
+
+// Summarize implements LLMBackend.
+func (b *openAICompatibleBackend) Summarize(ctx context.Context, model, prompt string) (string, error) {
+	request := ChatCompletionRequest{
+		Model:    model,
+		Messages: []OpenAIMessage{{Role: "user", Content: prompt}},
+	}
+
+	response, err := makeOpenAIAPICall(ctx, request, b.endpoint, b.apiKey)
+	if err != nil {
+		return "", fmt.Errorf("API call failed: %w", err)
+	}
+	return formatChunkResponse(response)
+}
+
+// SummarizeStream implements LLMBackend.
+func (b *openAICompatibleBackend) SummarizeStream(ctx context.Context, model, prompt string) <-chan Token {
+	request := ChatCompletionRequest{
+		Model:    model,
+		Messages: []OpenAIMessage{{Role: "user", Content: prompt}},
+	}
+
+	fragments, errs := streamChatCompletions(ctx, b.endpoint, b.apiKey, AppConfig.OpenAIRequestTimeout, request)
+
+	tokens := make(chan Token)
+	go func() {
+		defer close(tokens)
+		for fragments != nil || errs != nil {
+			select {
+			case fragment, ok := <-fragments:
+				if !ok {
+					fragments = nil
+					continue
+				}
+				tokens <- Token{Text: fragment}
+			case err, ok := <-errs:
+				if !ok {
+					errs = nil
+					continue
+				}
+				if err != nil {
+					tokens <- Token{Err: err}
+					return
+				}
+			}
+		}
+	}()
+	return tokens
+}
+
+// isValidModelName reports whether name is one of the models configured for
+// the active LLM_PROVIDER.
+func isValidModelName(name string) bool {
+	for _, model := range modelCatalog() {
+		if model == name {
+			return true
+		}
+	}
+	return false
+}
+
+// getDefaultModelName returns the first model in the active provider's
+// catalog, or "" if none are configured.
+func getDefaultModelName() string {
+	catalog := modelCatalog()
+	if len(catalog) == 0 {
+		return ""
+	}
+	return catalog[0]
+}
+
+// modelCatalog returns the configured model names for AppConfig's active
+// LLM_PROVIDER.
+func modelCatalog() []string {
+	switch AppConfig.LLMProvider {
+	case "", "openai":
+		return AppConfig.OpenAIModels
+	case "local", "ollama", "localai":
+		return AppConfig.LocalLLMModels
+	default:
+		return nil
+	}
+}
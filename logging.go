@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// requestContextKey distinguishes the context values this file defines from
+// any others a caller might stash on the same context.Context.
+type requestContextKey int
+
+const (
+	requestIDContextKey requestContextKey = iota
+	userIDContextKey
+	subredditContextKey
+	modelContextKey
+)
+
+// AppLogger is the process-wide structured logger, configured by
+// InitializeLogger from AppConfig.LogFormat and AppConfig.LogLevel.
+var AppLogger *slog.Logger
+
+// InitializeLogger configures AppLogger from AppConfig.LogFormat ("json" or
+// "text", default "text") and AppConfig.LogLevel ("debug", "info", "warn",
+// or "error", default "info").
+func InitializeLogger() {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(AppConfig.LogLevel)); err != nil {
+		level = slog.LevelInfo
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	if strings.EqualFold(AppConfig.LogFormat, "json") {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	AppLogger = slog.New(handler)
+}
+
+// newRequestID returns a short, unique, roughly time-ordered ID for
+// correlating every log line produced by a single incoming Telegram update.
+func newRequestID() string {
+	var random [4]byte
+	_, _ = rand.Read(random[:])
+	return fmt.Sprintf("%x%s", time.Now().UnixNano(), hex.EncodeToString(random[:]))
+}
+
+// withRequestContext stamps ctx with a freshly generated request ID and
+// userID, so every log line emitted while handling this update can be
+// correlated back to the originating user interaction. It's the
+// middleware-style entry point Bot.Start calls before handleMessage and
+// handleCallbackQuery.
+func withRequestContext(ctx context.Context, userID int64) context.Context {
+	ctx = context.WithValue(ctx, requestIDContextKey, newRequestID())
+	ctx = context.WithValue(ctx, userIDContextKey, userID)
+	return ctx
+}
+
+// withSubreddit returns a copy of ctx carrying subreddit, for log lines
+// further down the pipeline (subredditData, fetchTopPosts, summarizePosts).
+func withSubreddit(ctx context.Context, subreddit string) context.Context {
+	return context.WithValue(ctx, subredditContextKey, subreddit)
+}
+
+// withModel returns a copy of ctx carrying model, for log lines further
+// down the pipeline.
+func withModel(ctx context.Context, model string) context.Context {
+	return context.WithValue(ctx, modelContextKey, model)
+}
+
+// loggerFromContext returns AppLogger with whichever of request_id,
+// user_id, subreddit, and model fields ctx carries already attached, so
+// callers don't need to thread them through individually. Falls back to
+// slog.Default() if InitializeLogger hasn't run (e.g. in tests).
+func loggerFromContext(ctx context.Context) *slog.Logger {
+	logger := AppLogger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if requestID, ok := ctx.Value(requestIDContextKey).(string); ok {
+		logger = logger.With("request_id", requestID)
+	}
+	if userID, ok := ctx.Value(userIDContextKey).(int64); ok {
+		logger = logger.With("user_id", userID)
+	}
+	if subreddit, ok := ctx.Value(subredditContextKey).(string); ok {
+		logger = logger.With("subreddit", subreddit)
+	}
+	if model, ok := ctx.Value(modelContextKey).(string); ok {
+		logger = logger.With("model", model)
+	}
+	return logger
+}
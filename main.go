@@ -2,33 +2,55 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
-	"os/signal"
-	"syscall"
 )
 
 func main() {
+	guildID := flag.String("guild", os.Getenv("SUBTRENDS_DEV_GUILD_ID"), "register slash commands to this guild only, for near-instant propagation during development")
+	rmCmd := flag.Bool("rmcmd", false, "remove all registered slash commands on shutdown")
+	flag.Parse()
+
 	log.Println("Starting SubTrends Discord Bot...")
 
 	// Load configuration from environment variables
 	LoadConfig()
+	InitializeLogger()
 	InitializeAnthropicRateLimiter()
 	InitializeRedditRateLimiter()
+	InitializeTokenStore()
+
+	shutdownTelemetry, err := InitializeTelemetry(AppConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize telemetry: %v", err)
+	}
 
 	// Create Discord bot instance
 	bot, err := NewDiscordBot()
 	if err != nil {
 		log.Fatalf("Failed to create Discord bot: %v", err)
 	}
+	bot.devGuildID = *guildID
+	bot.removeCommandsOnShutdown = *rmCmd
 
-	// Create a context that will be canceled on interrupt
+	// Create a context that will be canceled on interrupt, so any in-flight
+	// Anthropic/Reddit HTTP call is aborted rather than left to finish in
+	// the background once shutdown begins.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Set up signal handling for graceful shutdown
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	// shutdownManager bounds the whole process's teardown by
+	// AppConfig.ShutdownTimeout: the bot stops first (it depends on the
+	// HTTP clients still being usable while it drains), then the HTTP
+	// clients are canceled, then telemetry flushes its last batch.
+	shutdownManager := NewShutdownManager()
+	shutdownManager.RegisterFunc("discord_bot", bot.Stop)
+	shutdownManager.RegisterFunc("http_clients", func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	shutdownManager.RegisterFunc("telemetry", shutdownTelemetry)
 
 	// Start bot in a goroutine
 	go func() {
@@ -41,15 +63,14 @@ func main() {
 	log.Println("SubTrends Discord Bot is now running. Press CTRL-C to exit.")
 
 	// Wait for termination signal
-	<-signalChan
+	WaitForSignal(ctx)
 	log.Println("Shutdown signal received, stopping bot...")
 
 	// Create a context with timeout for graceful shutdown
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), AppConfig.ShutdownTimeout)
 	defer shutdownCancel()
 
-	// Stop the bot
-	if err := bot.Stop(shutdownCtx); err != nil {
+	if err := shutdownManager.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Error during shutdown: %v", err)
 	}
 
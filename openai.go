@@ -3,6 +3,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -16,8 +17,11 @@ import (
 	"golang.org/x/time/rate"
 )
 
-// promptTemplate defines the template for the summarization request
-const promptTemplate = `Please provide an engaging and fun summary of these Reddit posts and discussions from r/%s. 
+// promptTemplate defines the template for the summarization request. The
+// response shape itself (trending_topics/community_pulse/hot_takes) is
+// enforced via ChatCompletionRequest.ResponseFormat, not by instructions
+// here, so this only needs to guide what goes into each field.
+const promptTemplate = `Please provide an engaging and fun summary of these Reddit posts and discussions from r/%s.
 
 Focus on:
 - Main themes and topics; group similar topics together
@@ -25,17 +29,15 @@ Focus on:
 - Notable trends, patterns, or controversies
 - Overall community sentiment and mood
 
-Format your response with:
-- ðŸ“Š TRENDING TOPICS: List the main themes with emoji indicators
-- ðŸ’¬ COMMUNITY PULSE: Describe the overall sentiment and notable discussions
-- ðŸ”¥ HOT TAKES: Highlight the most interesting or controversial opinions
+Return your summary as structured data:
+- trending_topics: one entry per main theme, each with a representative emoji, a short title, and a body describing it
+- community_pulse: a single entry (emoji, title, body) describing the overall sentiment and notable discussions
+- hot_takes: one entry per interesting or controversial opinion, each with emoji, title, and body
 
 Rules:
 - Be conversational and engaging, like you're telling a friend about what's happening on Reddit
-- Use appropriate emojis to make the summary more visually appealing
-- Don't reply with the summary for each post individually
+- Don't summarize each post individually — group into themes
 - Keep your tone friendly and slightly humorous where appropriate
-- Organize information in a clear, scannable format with bullet points and sections
 
 Posts to analyze:
 
@@ -59,8 +61,38 @@ type OpenAIMessage struct {
 
 // ChatCompletionRequest represents the structure of a request to the OpenAI API
 type ChatCompletionRequest struct {
-	Model    string          `json:"model"`
-	Messages []OpenAIMessage `json:"messages"`
+	Model          string          `json:"model"`
+	Messages       []OpenAIMessage `json:"messages"`
+	Stream         bool            `json:"stream,omitempty"`
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+}
+
+// ResponseFormat requests a specific response shape from an OpenAI-compatible
+// API. Type "json_schema" paired with JSONSchema constrains the model to
+// emit the given schema instead of freeform text.
+type ResponseFormat struct {
+	Type       string          `json:"type"`
+	JSONSchema *JSONSchemaSpec `json:"json_schema,omitempty"`
+}
+
+// JSONSchemaSpec is the OpenAI "json_schema" response_format payload.
+type JSONSchemaSpec struct {
+	Name   string      `json:"name"`
+	Strict bool        `json:"strict"`
+	Schema interface{} `json:"schema"`
+}
+
+// openaiStreamEvent is the subset of an OpenAI chat-completion SSE chunk we
+// care about: the incremental content delta, or a mid-stream error frame.
+type openaiStreamEvent struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
 }
 
 // ChatCompletionResponse represents the structure of a response from the OpenAI API
@@ -73,39 +105,174 @@ type ChatCompletionResponse struct {
 	Error *struct {
 		Message string `json:"message,omitempty"`
 	} `json:"error,omitempty"`
+	Usage *OpenAIUsage `json:"usage,omitempty"`
+}
+
+// OpenAIUsage reports the token accounting OpenAI-compatible APIs return
+// alongside a chat completion, used to populate the llm_tokens_total metric.
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
-// summarizePosts takes a string of Reddit posts and returns a summarized version using the OpenAI API
-func summarizePosts(subreddit, text string, model string) (string, error) {
+// summarizePosts takes a string of Reddit posts and returns a summarized
+// version using the OpenAI API. Results are cached by (subreddit, model,
+// content hash of text) so re-summarizing the same thread within the cache
+// TTL skips the API call entirely. ctx is honored both for the cache lookup
+// and the outbound API call, so canceling it (e.g. a web server draining
+// in-flight requests at shutdown) aborts the summary instead of letting it
+// run to completion in the background.
+func summarizePosts(ctx context.Context, subreddit, text string, model string) (string, error) {
 	log.Printf("INFO: Making OpenAI API call with model: %s", model)
 
 	if AppConfig.OpenAIAPIKey == "" {
 		return "", fmt.Errorf("OpenAI API key is not configured")
 	}
 
+	cacheKey := cacheKeyForSummary(subreddit, model, text)
+	var cached string
+	if getCached(ctx, cacheKey, &cached) {
+		log.Printf("INFO: Serving summary for r/%s from cache", subreddit)
+		return cached, nil
+	}
+
 	// Prepare the API request
 	request := createOpenAIRequest(model, text, subreddit)
 
-	// Create a context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), AppConfig.OpenAIRequestTimeout)
+	// Bound the API call by the configured timeout, but still under ctx so
+	// the caller's own cancellation takes effect too.
+	ctx, cancel := context.WithTimeout(ctx, AppConfig.OpenAIRequestTimeout)
 	defer cancel()
 
 	// Make the API call
-	response, err := makeOpenAIAPICall(ctx, request, AppConfig.OpenAIAPIKey)
+	response, err := makeOpenAIAPICall(ctx, request, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey)
 	if err != nil {
 		return "", fmt.Errorf("API call failed: %w", err)
 	}
 
-	// Format and return the response
-	return formatResponse(response)
+	// Format the response and cache it
+	summary, err := formatResponse(response)
+	if err != nil {
+		return "", err
+	}
+	setCached(ctx, cacheKey, summary, AppConfig.CacheTTL)
+	return summary, nil
 }
 
-// createOpenAIRequest creates a request structure for the OpenAI API
+// summarizePostsStreamOpenAI is the streaming counterpart to summarizePosts.
+// It issues the request with "stream": true and emits each content delta
+// fragment on the returned text channel as it arrives, closing both channels
+// when the stream ends. Callers should treat anything on the error channel
+// as fatal and fall back to summarizePosts.
+func summarizePostsStreamOpenAI(ctx context.Context, subreddit, text, model string) (<-chan string, <-chan error) {
+	if AppConfig.OpenAIAPIKey == "" {
+		errs := make(chan error, 1)
+		errs <- fmt.Errorf("OpenAI API key is not configured")
+		close(errs)
+		fragments := make(chan string)
+		close(fragments)
+		return fragments, errs
+	}
+
+	request := createOpenAIRequest(model, text, subreddit)
+	return streamChatCompletions(ctx, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey, AppConfig.OpenAIRequestTimeout, request)
+}
+
+// streamChatCompletions issues request (with Stream forced on) against
+// endpoint, an OpenAI-compatible chat-completions API, and emits each
+// content delta fragment on the returned text channel as it arrives,
+// closing both channels when the stream ends. Callers should treat anything
+// on the error channel as fatal and fall back to a non-streaming call.
+func streamChatCompletions(ctx context.Context, endpoint, apiKey string, timeout time.Duration, request ChatCompletionRequest) (<-chan string, <-chan error) {
+	fragments := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(fragments)
+		defer close(errs)
+
+		request.Stream = true
+
+		if err := openaiLimiter.Wait(ctx); err != nil {
+			errs <- fmt.Errorf("rate limit wait failed: %w", err)
+			return
+		}
+
+		requestBody, err := json.Marshal(request)
+		if err != nil {
+			errs <- fmt.Errorf("failed to marshal request: %w", err)
+			return
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(requestBody))
+		if err != nil {
+			errs <- fmt.Errorf("failed to create HTTP request: %w", err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+apiKey)
+		}
+		req.Header.Set("Accept", "text/event-stream")
+
+		client := &http.Client{Timeout: timeout}
+		resp, err := client.Do(req)
+		if err != nil {
+			errs <- fmt.Errorf("HTTP request failed: %w", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			errs <- fmt.Errorf("API returned non-200 status code %d: %s", resp.StatusCode, string(bodyBytes))
+			return
+		}
+
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "data: ") {
+				continue // keep-alive comment lines and blank lines between events
+			}
+
+			payload := strings.TrimPrefix(line, "data: ")
+			if payload == "[DONE]" {
+				return
+			}
+
+			var event openaiStreamEvent
+			if err := json.Unmarshal([]byte(payload), &event); err != nil {
+				log.Printf("WARNING: Failed to parse OpenAI stream event: %v", err)
+				continue
+			}
+
+			if event.Error != nil && event.Error.Message != "" {
+				errs <- fmt.Errorf("API error: %s", event.Error.Message)
+				return
+			}
+
+			if len(event.Choices) > 0 && event.Choices[0].Delta.Content != "" {
+				fragments <- event.Choices[0].Delta.Content
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			errs <- fmt.Errorf("error reading stream: %w", err)
+		}
+	}()
+
+	return fragments, errs
+}
+
+// createOpenAIRequest creates a request structure for the OpenAI API. The
+// response is constrained to the SummaryResult JSON shape via
+// summaryResponseFormat so formatResponse can parse it directly instead of
+// regex-matching a freeform reply.
 func createOpenAIRequest(model, text, subredditName string) ChatCompletionRequest {
 	// Format the prompt with the Reddit data and subreddit name
 	prompt := fmt.Sprintf(promptTemplate, subredditName, text)
 
-	// Create the request structure (keep minimal; rely on server defaults)
 	return ChatCompletionRequest{
 		Model: model,
 		Messages: []OpenAIMessage{
@@ -114,11 +281,13 @@ func createOpenAIRequest(model, text, subredditName string) ChatCompletionReques
 				Content: prompt,
 			},
 		},
+		ResponseFormat: summaryResponseFormat,
 	}
 }
 
-// makeOpenAIAPICall sends a request to the OpenAI API and returns the response
-func makeOpenAIAPICall(ctx context.Context, request ChatCompletionRequest, apiKey string) (*ChatCompletionResponse, error) {
+// makeOpenAIAPICall sends a request to endpoint (an OpenAI-compatible
+// chat-completions API) and returns the response.
+func makeOpenAIAPICall(ctx context.Context, request ChatCompletionRequest, endpoint, apiKey string) (*ChatCompletionResponse, error) {
 	// Apply rate limiting
 	if err := openaiLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limit wait failed: %w", err)
@@ -131,19 +300,19 @@ func makeOpenAIAPICall(ctx context.Context, request ChatCompletionRequest, apiKe
 	}
 
 	// Create the HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", AppConfig.OpenAIAPIEndpoint, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(requestBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+apiKey)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
 
 	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: AppConfig.OpenAIRequestTimeout,
-	}
+	client := instrumentedClient(AppConfig.OpenAIRequestTimeout)
 
 	// Send the request
 	startTime := time.Now()
@@ -174,32 +343,16 @@ func makeOpenAIAPICall(ctx context.Context, request ChatCompletionRequest, apiKe
 		return nil, fmt.Errorf("API error: %s", response.Error.Message)
 	}
 
+	if response.Usage != nil {
+		recordLLMTokens(request.Model, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	}
+
 	return &response, nil
 }
 
-// formatResponse extracts and formats the text from the OpenAI API response
+// formatResponse parses the structured summary from the OpenAI API response
+// and renders it as Markdown, prefixed with the configured summary header.
 func formatResponse(response *ChatCompletionResponse) (string, error) {
-	if response == nil {
-		return "", fmt.Errorf("nil response received")
-	}
-
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("empty content in response")
-	}
-
-	// Extract the text from the response
-	text := response.Choices[0].Message.Content
-	if text == "" {
-		return "", fmt.Errorf("empty text in response content")
-	}
-
-	// Ensure proper Markdown formatting (preserve simple behavior)
-	if !strings.Contains(text, "*") {
-		text = strings.ReplaceAll(text, "TRENDING TOPICS", "*TRENDING TOPICS*")
-		text = strings.ReplaceAll(text, "COMMUNITY PULSE", "*COMMUNITY PULSE*")
-		text = strings.ReplaceAll(text, "HOT TAKES", "*HOT TAKES*")
-	}
-
-	// Format the response with a header
-	return AppConfig.SummaryHeader + text, nil
+	markdown, _, err := formatStructuredResponse(response)
+	return markdown, err
 }
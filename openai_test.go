@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -40,6 +42,8 @@ func TestFormatResponseErrors(t *testing.T) {
 	}
 }
 
+const testSummaryJSON = `{"trending_topics":[{"emoji":"🎮","title":"Gaming","body":"Big release discussion"}],"community_pulse":{"emoji":"😊","title":"Upbeat","body":"Mostly positive"},"hot_takes":[{"emoji":"🔥","title":"Hot take","body":"Controversial opinion"}]}`
+
 func TestFormatResponseHeaderAndEmphasis(t *testing.T) {
 	// Minimal successful response
 	var c struct {
@@ -47,7 +51,7 @@ func TestFormatResponseHeaderAndEmphasis(t *testing.T) {
 			Content string `json:"content"`
 		} `json:"message"`
 	}
-	c.Message.Content = "TRENDING TOPICS\nCOMMUNITY PULSE\nHOT TAKES"
+	c.Message.Content = testSummaryJSON
 	resp := &ChatCompletionResponse{Choices: []struct {
 		Message struct {
 			Content string "json:\"content\""
@@ -65,6 +69,9 @@ func TestFormatResponseHeaderAndEmphasis(t *testing.T) {
 	if out[:5] != "HDR\n\n" {
 		t.Fatalf("header not applied: %q", out)
 	}
+	if !strings.Contains(out, "*TRENDING TOPICS*") || !strings.Contains(out, "*COMMUNITY PULSE*") || !strings.Contains(out, "*HOT TAKES*") {
+		t.Fatalf("expected rendered sections, got: %q", out)
+	}
 }
 
 func TestMakeOpenAIAPICallRateLimiterContextCancel(t *testing.T) {
@@ -82,7 +89,7 @@ func TestMakeOpenAIAPICallRateLimiterContextCancel(t *testing.T) {
 
 	// Make a minimal request object
 	req := createOpenAIRequest("gpt-5-mini", "X", "sub")
-	_, err := makeOpenAIAPICall(ctx, req, AppConfig.OpenAIAPIKey)
+	_, err := makeOpenAIAPICall(ctx, req, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey)
 	if err == nil {
 		t.Fatal("expected error due to context timeout/limiter")
 	}
@@ -96,7 +103,12 @@ func TestMakeOpenAIAPICallSuccessAndAPIError(t *testing.T) {
 	// Happy path server
 	okSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"TRENDING TOPICS\nCOMMUNITY PULSE\nHOT TAKES"}}]}`))
+		body, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"content": testSummaryJSON}},
+			},
+		})
+		_, _ = w.Write(body)
 	}))
 	defer okSrv.Close()
 
@@ -113,7 +125,7 @@ func TestMakeOpenAIAPICallSuccessAndAPIError(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	resp, err := makeOpenAIAPICall(ctx, req, AppConfig.OpenAIAPIKey)
+	resp, err := makeOpenAIAPICall(ctx, req, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey)
 	if err != nil {
 		t.Fatalf("unexpected err: %v", err)
 	}
@@ -132,7 +144,7 @@ func TestMakeOpenAIAPICallSuccessAndAPIError(t *testing.T) {
 	withEnv(t, "OPENAI_API_ENDPOINT", errSrv.URL)
 	LoadConfig()
 
-	_, err = makeOpenAIAPICall(ctx, req, AppConfig.OpenAIAPIKey)
+	_, err = makeOpenAIAPICall(ctx, req, AppConfig.OpenAIAPIEndpoint, AppConfig.OpenAIAPIKey)
 	if err == nil {
 		t.Fatalf("expected error for non-200 response")
 	}
@@ -142,7 +154,12 @@ func TestSummarizePostsSuccess(t *testing.T) {
 	// server returns valid choices
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"TRENDING TOPICS\nCOMMUNITY PULSE\nHOT TAKES"}}]}`))
+		body, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]interface{}{"content": testSummaryJSON}},
+			},
+		})
+		_, _ = w.Write(body)
 	}))
 	defer srv.Close()
 
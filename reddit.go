@@ -1,16 +1,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/redis/go-redis/v9"
+	"github.com/valyala/fastjson"
 	"golang.org/x/time/rate"
 )
 
@@ -29,23 +37,162 @@ const (
 	requestsPerSecond = 1
 	burstSize         = 5
 
+	// RequestRemainingBuffer is the minimum budget Reddit must still report
+	// via x-ratelimit-remaining before makeRequest will proceed; below this,
+	// it pauses until x-ratelimit-reset elapses. Mirrors the strategy used by
+	// the apollo-backend Reddit client.
+	RequestRemainingBuffer = 50
+
+	// skipHeaderLimitHeader, when set to skipHeaderLimitValue on an outgoing
+	// request, tells attemptRequest to bypass headerLimiter.wait() for
+	// that request. The OAuth token endpoint isn't part of the oauth.reddit.com
+	// rate-limit pool the header budget tracks, so it would otherwise be
+	// throttled by quota that has nothing to do with it. The header never
+	// reaches Reddit; attemptRequest strips it before sending.
+	skipHeaderLimitHeader = "X-Subtrends-Skip-Header-Limit"
+	skipHeaderLimitValue  = "1"
+
 	// Token caching
 	tokenExpiryBuffer = 5 * time.Minute
-	tokenFilePath     = "reddit_token.json"
 )
 
+// Typed errors mapped from Reddit's HTTP status codes in makeRequest, so
+// callers like handleTrendAnalysis can surface an accurate message instead
+// of a generic failure. Use errors.Is to check for these, since
+// fetchTopPosts/fetchTopComments/getRedditAccessToken wrap them with %w.
 var (
-	// Token caching
+	ErrSubredditNotFound    = errors.New("subreddit not found")
+	ErrSubredditPrivate     = errors.New("subreddit is private")
+	ErrSubredditQuarantined = errors.New("subreddit is quarantined")
+	ErrSubredditBanned      = errors.New("subreddit is banned")
+	ErrOauthRevoked         = errors.New("reddit oauth token revoked or invalid")
+	ErrRateLimited          = errors.New("reddit rate limit exceeded")
+	ErrTimeout              = errors.New("reddit request timed out")
+)
+
+// User agent for Reddit API requests
+var redditUserAgent = getEnvOrDefault("REDDIT_USER_AGENT", "SubTrends/1.0")
+
+// RedditClient bundles everything a call to Reddit's API needs: the static
+// and server-reported rate limiters, a pooled fastjson parser for decoding
+// listings, and the cached OAuth token (backed by a TokenStore). It replaces
+// what used to be package-level globals, so tests can construct isolated
+// clients instead of mutating shared state.
+type RedditClient struct {
+	// limiter enforces a static requests-per-second budget.
+	limiter *rate.Limiter
+
+	// headerLimiter tracks Reddit's own server-reported rate budget
+	// (x-ratelimit-remaining/used/reset headers), complementing limiter with
+	// what Reddit actually tells us per response.
+	headerLimiter *redditHeaderState
+
+	// parserPool hands out reusable fastjson parsers for decoding listings
+	// and comment trees, avoiding per-request allocation.
+	parserPool *fastjson.ParserPool
+
+	tokenStore TokenStore
+
 	tokenMutex      sync.RWMutex
 	cachedToken     string
 	tokenExpiration time.Time
+}
 
-	// Rate limiter
-	redditLimiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burstSize)
+// NewRedditClient creates a RedditClient backed by store for OAuth token
+// persistence.
+func NewRedditClient(store TokenStore) *RedditClient {
+	return &RedditClient{
+		limiter:       rate.NewLimiter(rate.Limit(requestsPerSecond), burstSize),
+		headerLimiter: &redditHeaderState{},
+		parserPool:    &fastjson.ParserPool{},
+		tokenStore:    store,
+	}
+}
 
-	// User agent for Reddit API requests
-	redditUserAgent = getEnvOrDefault("REDDIT_USER_AGENT", "SubTrends/1.0")
-)
+// defaultRedditClient is the RedditClient backing the package-level
+// fetchTopPosts/fetchListing/fetchTopComments/getRedditAccessToken wrappers
+// that the rest of the package calls. It's set up by InitializeTokenStore at
+// startup.
+var defaultRedditClient *RedditClient
+
+// redditHeaderState holds the most recently observed Reddit rate-limit
+// headers, shared across the concurrent requests fetchTopComments fires off.
+type redditHeaderState struct {
+	mu        sync.Mutex
+	remaining float64
+	used      float64
+	resetAt   time.Time
+}
+
+// RateLimitSnapshot is a point-in-time read of the Reddit rate-limit budget
+// reported in the x-ratelimit-* headers, exposed so callers like the Discord
+// handler can surface it in logs or user-facing error messages.
+type RateLimitSnapshot struct {
+	Remaining float64
+	Used      float64
+	ResetAt   time.Time
+}
+
+// String renders the snapshot as "X requests remaining until Y".
+func (snap RateLimitSnapshot) String() string {
+	if snap.ResetAt.IsZero() {
+		return "rate limit budget unknown"
+	}
+	return fmt.Sprintf("%.0f requests remaining until %s", snap.Remaining, snap.ResetAt.Format(time.Kitchen))
+}
+
+// Snapshot returns the most recently observed Reddit rate-limit budget.
+func (s *redditHeaderState) Snapshot() RateLimitSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return RateLimitSnapshot{Remaining: s.remaining, Used: s.used, ResetAt: s.resetAt}
+}
+
+// wait pauses the caller if the last response reported a remaining budget
+// below RequestRemainingBuffer, sleeping until resetAt.
+func (s *redditHeaderState) wait() {
+	snap := s.Snapshot()
+
+	if snap.ResetAt.IsZero() || snap.Remaining >= RequestRemainingBuffer {
+		return
+	}
+
+	if wait := time.Until(snap.ResetAt); wait > 0 {
+		log.Printf("INFO: Reddit rate budget low (%s), pausing %s until reset", snap, wait)
+		time.Sleep(wait)
+	}
+}
+
+// update records the remaining/used/reset values resp reported, if any.
+func (s *redditHeaderState) update(resp *http.Response) {
+	remainingHeader := resp.Header.Get("x-ratelimit-remaining")
+	usedHeader := resp.Header.Get("x-ratelimit-used")
+	resetHeader := resp.Header.Get("x-ratelimit-reset")
+	if remainingHeader == "" || resetHeader == "" {
+		return
+	}
+
+	remaining, err := strconv.ParseFloat(remainingHeader, 64)
+	if err != nil {
+		return
+	}
+	resetSeconds, err := strconv.Atoi(resetHeader)
+	if err != nil {
+		return
+	}
+	used, err := strconv.ParseFloat(usedHeader, 64)
+	if err != nil {
+		used = s.Snapshot().Used // header missing/malformed; keep the last known value
+	}
+
+	s.mu.Lock()
+	s.remaining = remaining
+	s.used = used
+	s.resetAt = time.Now().Add(time.Duration(resetSeconds) * time.Second)
+	s.mu.Unlock()
+
+	redditRateLimitRemaining.Set(remaining)
+}
 
 // TokenData represents the structure of the token file
 type TokenData struct {
@@ -53,12 +200,6 @@ type TokenData struct {
 	ExpiresAt   time.Time `json:"expires_at"`
 }
 
-// RedditTokenResponse represents the OAuth token response from Reddit
-type RedditTokenResponse struct {
-	AccessToken string        `json:"access_token"`
-	ExpiresIn   time.Duration `json:"expires_in"`
-}
-
 // RedditPost represents a Reddit post with essential fields
 type RedditPost struct {
 	Title     string `json:"title"`
@@ -67,132 +208,351 @@ type RedditPost struct {
 	Permalink string `json:"permalink"`
 }
 
-// RedditResponse represents the full response from Reddit's post listing API
-type RedditResponse struct {
-	Data struct {
-		Children []struct {
-			Data RedditPost `json:"data"`
-		} `json:"children"`
-	} `json:"data"`
+// RedditRateLimitSnapshot returns the most recently observed Reddit
+// rate-limit budget, so callers like the Discord bot can log or surface it
+// (e.g. "X requests remaining until Y") alongside a rate-limit error.
+func RedditRateLimitSnapshot() RateLimitSnapshot {
+	return defaultRedditClient.headerLimiter.Snapshot()
 }
 
-// RedditComment represents the comment response structure from Reddit
-type RedditComment struct {
-	Data struct {
-		Children []struct {
-			Data struct {
-				Body string `json:"body"`
-				Ups  int    `json:"ups"`
-			} `json:"data"`
-		} `json:"children"`
-	} `json:"data"`
+// isQuarantinedBody reports whether a 403 response body is Reddit's
+// quarantine interstitial rather than an ordinary private-subreddit
+// rejection; Reddit marks these with `"reason": "quarantined"` in the JSON
+// error payload.
+func isQuarantinedBody(body []byte) bool {
+	var errResp struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return false
+	}
+	return errResp.Reason == "quarantined"
 }
 
-// makeRequest handles HTTP requests with rate limiting and common error handling
-func makeRequest(req *http.Request) (*http.Response, error) {
-	// Apply rate limiting
+// isBannedBody reports whether a 404 response body is Reddit's ban
+// interstitial rather than an ordinary nonexistent-subreddit 404; Reddit
+// marks these with `"reason": "banned"` in the JSON error payload.
+func isBannedBody(body []byte) bool {
+	var errResp struct {
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &errResp); err != nil {
+		return false
+	}
+	return errResp.Reason == "banned"
+}
+
+// redditRetryBackoff is the bounded retry schedule makeRequest walks through
+// on 429/5xx/timeout responses before giving up and returning the last error.
+var redditRetryBackoff = []time.Duration{200 * time.Millisecond, 500 * time.Millisecond, 1 * time.Second, 2 * time.Second, 4 * time.Second}
+
+// makeRequest handles HTTP requests with rate limiting, Reddit's own
+// response-header-driven pacing, and common error handling. 429/5xx
+// responses are retried with a bounded backoff schedule.
+func (c *RedditClient) makeRequest(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, fmt.Errorf("cannot retry request with non-rewindable body: %w", lastErr)
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body for retry: %w", err)
+			}
+			req.Body = body
+		}
+
+		resp, retryable, err := c.attemptRequest(req)
+		if err == nil {
+			return resp, nil
+		}
+		lastErr = err
+
+		if !retryable || attempt >= len(redditRetryBackoff) {
+			return nil, lastErr
+		}
+
+		delay := redditRetryBackoff[attempt]
+		log.Printf("WARNING: Reddit request to %s %s failed (%v), retrying in %s (attempt %d/%d)",
+			req.Method, req.URL.String(), err, delay, attempt+1, len(redditRetryBackoff))
+		time.Sleep(delay)
+	}
+}
+
+// attemptRequest performs a single HTTP round trip, applying rate limiting
+// and classifying the outcome. retryable is true for 429/5xx responses,
+// which makeRequest retries with backoff.
+func (c *RedditClient) attemptRequest(req *http.Request) (resp *http.Response, retryable bool, err error) {
 	ctx := req.Context()
 	log.Printf("INFO: Waiting for rate limiter before making request to: %s %s", req.Method, req.URL.String())
-	if err := redditLimiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit wait failed: %w", err)
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, false, fmt.Errorf("rate limit wait failed: %w", err)
 	}
 
-	// Set a timeout for the request
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	skipHeaderLimit := req.Header.Get(skipHeaderLimitHeader) == skipHeaderLimitValue
+	req.Header.Del(skipHeaderLimitHeader)
+	if !skipHeaderLimit {
+		c.headerLimiter.wait()
 	}
 
+	// Set a timeout for the request
+	client := instrumentedClient(10 * time.Second)
+
 	log.Printf("INFO: Sending request: %s %s", req.Method, req.URL.String())
-	resp, err := client.Do(req)
+	resp, err = client.Do(req)
 	if err != nil {
 		log.Printf("ERROR: Request failed: %s %s - %v", req.Method, req.URL.String(), err)
-		return nil, fmt.Errorf("request failed: %w", err)
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return nil, true, fmt.Errorf("%w: %v", ErrTimeout, err)
+		}
+		return nil, false, fmt.Errorf("request failed: %w", err)
 	}
 
-	log.Printf("INFO: Received response: %s %s - Status: %d", req.Method, req.URL.String(), resp.StatusCode)
+	c.headerLimiter.update(resp)
+	log.Printf("INFO: Received response: %s %s - Status: %d - remaining: %s, used: %s, reset: %s",
+		req.Method, req.URL.String(), resp.StatusCode,
+		resp.Header.Get("x-ratelimit-remaining"), resp.Header.Get("x-ratelimit-used"), resp.Header.Get("x-ratelimit-reset"))
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		log.Printf("ERROR: Unexpected status code: %s %s - Status: %d - Body: %s", req.Method, req.URL.String(), resp.StatusCode, string(body))
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return nil, true, fmt.Errorf("%w (%s): %s", ErrRateLimited, c.headerLimiter.Snapshot(), string(body))
+		}
+		if resp.StatusCode >= 500 {
+			return nil, true, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+		}
+
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			if isBannedBody(body) {
+				return nil, false, fmt.Errorf("%w (status %d): %s", ErrSubredditBanned, resp.StatusCode, string(body))
+			}
+			return nil, false, fmt.Errorf("%w (status %d): %s", ErrSubredditNotFound, resp.StatusCode, string(body))
+		case http.StatusForbidden:
+			if isQuarantinedBody(body) {
+				return nil, false, fmt.Errorf("%w (status %d): %s", ErrSubredditQuarantined, resp.StatusCode, string(body))
+			}
+			return nil, false, fmt.Errorf("%w (status %d): %s", ErrSubredditPrivate, resp.StatusCode, string(body))
+		case http.StatusUnauthorized:
+			c.invalidateRedditToken()
+			return nil, false, fmt.Errorf("%w (status %d): %s", ErrOauthRevoked, resp.StatusCode, string(body))
+		}
+		return nil, false, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return resp, false, nil
+}
+
+// makeRequest delegates to defaultRedditClient, for the many call sites that
+// don't need an isolated client.
+func makeRequest(req *http.Request) (*http.Response, error) {
+	return defaultRedditClient.makeRequest(req)
+}
+
+// TokenStore persists the Reddit OAuth token somewhere durable so it
+// survives a restart, or (with a shared backend like Redis) so multiple
+// subtrends replicas can reuse one token instead of each minting their own
+// against Reddit's per-app quota.
+type TokenStore interface {
+	Get(ctx context.Context) (TokenData, error)
+	Set(ctx context.Context, data TokenData) error
+	Delete(ctx context.Context) error
+}
+
+// redditTokenStore is the process-wide TokenStore, selected by
+// InitializeTokenStore from AppConfig.TokenStore.
+var redditTokenStore TokenStore
+
+// InitializeTokenStore sets up redditTokenStore from AppConfig: Redis-backed
+// when TokenStore is "redis" (requires RedisURL), file-backed otherwise. It
+// also (re)builds defaultRedditClient around the resulting store.
+func InitializeTokenStore() {
+	if AppConfig.TokenStore == "redis" {
+		store, err := newRedisTokenStore(AppConfig.RedisURL)
+		if err != nil {
+			log.Printf("WARNING: Failed to initialize Redis token store (%v), falling back to file", err)
+		} else {
+			redditTokenStore = store
+			defaultRedditClient = NewRedditClient(redditTokenStore)
+			return
+		}
 	}
+	redditTokenStore = newFileTokenStore(AppConfig.RedditTokenFilePath)
+	defaultRedditClient = NewRedditClient(redditTokenStore)
+}
+
+// fileTokenStore persists the token as JSON in a single file on the local
+// filesystem. It's the default TokenStore and matches the pre-Redis
+// behavior: fine for a single bot instance, but not shared across replicas.
+type fileTokenStore struct {
+	path string
+}
 
-	return resp, nil
+func newFileTokenStore(path string) *fileTokenStore {
+	return &fileTokenStore{path: path}
 }
 
-// saveTokenToFile saves the token and its expiration time to a file
-func saveTokenToFile(token string, expiresIn time.Duration) error {
-	tokenData := TokenData{
-		AccessToken: token,
-		ExpiresAt:   time.Now().Add(time.Second * expiresIn),
+func (s *fileTokenStore) Get(ctx context.Context) (TokenData, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return TokenData{}, nil
+		}
+		return TokenData{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var tokenData TokenData
+	if err := json.Unmarshal(data, &tokenData); err != nil {
+		return TokenData{}, fmt.Errorf("failed to unmarshal token data: %w", err)
 	}
+	return tokenData, nil
+}
 
+func (s *fileTokenStore) Set(ctx context.Context, tokenData TokenData) error {
 	data, err := json.MarshalIndent(tokenData, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal token data: %w", err)
 	}
-
-	if err := os.WriteFile(tokenFilePath, data, 0644); err != nil {
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
 		return fmt.Errorf("failed to write token file: %w", err)
 	}
+	return nil
+}
 
-	log.Printf("INFO: Token saved to file, expires at %v", tokenData.ExpiresAt)
+func (s *fileTokenStore) Delete(ctx context.Context) error {
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove token file: %w", err)
+	}
 	return nil
 }
 
-// readTokenFromFile attempts to read the token from the file
-func readTokenFromFile() (string, error) {
-	data, err := os.ReadFile(tokenFilePath)
+// redisTokenKey is where redisTokenStore keeps the shared Reddit token.
+const redisTokenKey = "subtrends:reddit:token"
+
+// redisTokenStore persists the token in Redis, so every subtrends replica
+// pointed at the same Redis instance shares one Reddit OAuth token.
+type redisTokenStore struct {
+	client *redis.Client
+}
+
+func newRedisTokenStore(redisURL string) (*redisTokenStore, error) {
+	opts, err := redis.ParseURL(redisURL)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return "", nil
+		return nil, fmt.Errorf("failed to parse REDIS_URL: %w", err)
+	}
+	return &redisTokenStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *redisTokenStore) Get(ctx context.Context) (TokenData, error) {
+	raw, err := s.client.Get(ctx, redisTokenKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return TokenData{}, nil
 		}
-		return "", fmt.Errorf("failed to read token file: %w", err)
+		return TokenData{}, fmt.Errorf("redis GET %s failed: %w", redisTokenKey, err)
 	}
 
 	var tokenData TokenData
-	if err := json.Unmarshal(data, &tokenData); err != nil {
-		return "", fmt.Errorf("failed to unmarshal token data: %w", err)
+	if err := json.Unmarshal([]byte(raw), &tokenData); err != nil {
+		return TokenData{}, fmt.Errorf("failed to unmarshal token data: %w", err)
+	}
+	return tokenData, nil
+}
+
+func (s *redisTokenStore) Set(ctx context.Context, tokenData TokenData) error {
+	raw, err := json.Marshal(tokenData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token data: %w", err)
+	}
+	if err := s.client.Set(ctx, redisTokenKey, raw, 0).Err(); err != nil {
+		return fmt.Errorf("redis SET %s failed: %w", redisTokenKey, err)
+	}
+	return nil
+}
+
+func (s *redisTokenStore) Delete(ctx context.Context) error {
+	if err := s.client.Del(ctx, redisTokenKey).Err(); err != nil {
+		return fmt.Errorf("redis DEL %s failed: %w", redisTokenKey, err)
+	}
+	return nil
+}
+
+// invalidateRedditToken clears the in-memory and store-backed cached OAuth
+// token, forcing getRedditAccessToken to fetch a fresh one on its next call.
+// Called when Reddit responds with 401, since that means the token we have
+// is revoked or otherwise no longer valid.
+func (c *RedditClient) invalidateRedditToken() {
+	c.tokenMutex.Lock()
+	c.cachedToken = ""
+	c.tokenExpiration = time.Time{}
+	c.tokenMutex.Unlock()
+
+	if c.tokenStore == nil {
+		return
+	}
+	if err := c.tokenStore.Delete(context.Background()); err != nil {
+		log.Printf("WARNING: Failed to remove cached token from store: %v", err)
+	}
+}
+
+// invalidateRedditToken delegates to defaultRedditClient.
+func invalidateRedditToken() {
+	defaultRedditClient.invalidateRedditToken()
+}
+
+// readTokenFromStore attempts to read a still-valid token from c.tokenStore,
+// returning "" (with no error) if there isn't one yet or it's expired.
+func (c *RedditClient) readTokenFromStore() (string, error) {
+	if c.tokenStore == nil {
+		return "", nil
 	}
 
-	// Check if token is expired or about to expire
-	if time.Now().Add(tokenExpiryBuffer).After(tokenData.ExpiresAt) {
+	tokenData, err := c.tokenStore.Get(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if tokenData.AccessToken == "" || time.Now().Add(tokenExpiryBuffer).After(tokenData.ExpiresAt) {
 		return "", nil
 	}
 
-	log.Printf("INFO: Token loaded from file, expires at %v", tokenData.ExpiresAt)
+	log.Printf("INFO: Token loaded from store, expires at %v", tokenData.ExpiresAt)
 	return tokenData.AccessToken, nil
 }
 
-// getRedditAccessToken obtains an OAuth token for Reddit API access, with caching and file persistence
-func getRedditAccessToken() (string, error) {
-	// First try to read from file
-	token, err := readTokenFromFile()
+// getRedditAccessToken obtains an OAuth token for Reddit API access, with
+// in-memory caching backed by c.tokenStore for persistence (and sharing
+// across replicas, when the store is Redis-backed).
+func (c *RedditClient) getRedditAccessToken() (string, error) {
+	// First try to read from the store
+	token, err := c.readTokenFromStore()
 	if err != nil {
-		log.Printf("WARNING: Failed to read token from file: %v", err)
+		log.Printf("WARNING: Failed to read token from store: %v", err)
 	} else if token != "" {
 		return token, nil
 	}
 
 	// Check if cached token is still valid (with buffer time)
-	tokenMutex.RLock()
-	if time.Now().Add(tokenExpiryBuffer).Before(tokenExpiration) && cachedToken != "" {
-		token := cachedToken
-		tokenMutex.RUnlock()
-		log.Printf("INFO: Using cached Reddit access token, expires in %v", time.Until(tokenExpiration))
+	c.tokenMutex.RLock()
+	if time.Now().Add(tokenExpiryBuffer).Before(c.tokenExpiration) && c.cachedToken != "" {
+		token := c.cachedToken
+		c.tokenMutex.RUnlock()
+		log.Printf("INFO: Using cached Reddit access token, expires in %v", time.Until(c.tokenExpiration))
 		return token, nil
 	}
-	tokenMutex.RUnlock()
+	c.tokenMutex.RUnlock()
 
 	// Need to get a new token
-	tokenMutex.Lock()
-	defer tokenMutex.Unlock()
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
 
 	// Double-check after acquiring write lock
-	if time.Now().Add(tokenExpiryBuffer).Before(tokenExpiration) && cachedToken != "" {
-		log.Printf("INFO: Using cached Reddit access token, expires in %v", time.Until(tokenExpiration))
-		return cachedToken, nil
+	if time.Now().Add(tokenExpiryBuffer).Before(c.tokenExpiration) && c.cachedToken != "" {
+		log.Printf("INFO: Using cached Reddit access token, expires in %v", time.Until(c.tokenExpiration))
+		return c.cachedToken, nil
 	}
 
 	log.Printf("INFO: Requesting new Reddit access token")
@@ -213,37 +573,98 @@ func getRedditAccessToken() (string, error) {
 	req.SetBasicAuth(clientID, clientSecret)
 	req.Header.Set("User-Agent", redditUserAgent)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// The token endpoint isn't part of the oauth.reddit.com pool that
+	// headerLimiter tracks, so don't let its budget throttle auth.
+	req.Header.Set(skipHeaderLimitHeader, skipHeaderLimitValue)
 
-	resp, err := makeRequest(req)
+	resp, err := c.makeRequest(req)
 	if err != nil {
 		return "", fmt.Errorf("token request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var tokenResp RedditTokenResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return "", fmt.Errorf("failed to decode token response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
 	}
 
-	if tokenResp.AccessToken == "" {
+	parser := c.parserPool.Get()
+	defer c.parserPool.Put(parser)
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	accessToken := string(v.GetStringBytes("access_token"))
+	if accessToken == "" {
 		return "", fmt.Errorf("empty access token received")
 	}
+	expiresIn := time.Duration(v.GetInt("expires_in"))
 
 	// Cache the token in memory
-	cachedToken = tokenResp.AccessToken
-	tokenExpiration = time.Now().Add(time.Second * tokenResp.ExpiresIn)
-
-	// Save token to file
-	if err := saveTokenToFile(tokenResp.AccessToken, tokenResp.ExpiresIn); err != nil {
-		log.Printf("WARNING: Failed to save token to file: %v", err)
+	c.cachedToken = accessToken
+	c.tokenExpiration = time.Now().Add(time.Second * expiresIn)
+
+	// Persist to the store so it survives a restart (and, with a shared
+	// backend, is reusable by other replicas).
+	if c.tokenStore != nil {
+		tokenData := TokenData{
+			AccessToken: accessToken,
+			ExpiresAt:   c.tokenExpiration,
+		}
+		if err := c.tokenStore.Set(context.Background(), tokenData); err != nil {
+			log.Printf("WARNING: Failed to save token to store: %v", err)
+		}
 	}
 
-	log.Printf("INFO: New Reddit token acquired, expires in %v", tokenResp.ExpiresIn*time.Second)
-	return cachedToken, nil
+	log.Printf("INFO: New Reddit token acquired, expires in %v", expiresIn*time.Second)
+	return c.cachedToken, nil
 }
 
-// fetchTopPosts fetches top posts from a subreddit
-func fetchTopPosts(subreddit, token string) ([]RedditPost, error) {
+// getRedditAccessToken delegates to defaultRedditClient.
+func getRedditAccessToken() (string, error) {
+	return defaultRedditClient.getRedditAccessToken()
+}
+
+// ListingSort is one of the post-listing sorts Reddit's API supports.
+type ListingSort string
+
+// Supported listing sorts. Only SortTop and SortControversial accept a
+// timeframe ("t" query parameter) — see sortAcceptsTimeframe.
+const (
+	SortHot           ListingSort = "hot"
+	SortNew           ListingSort = "new"
+	SortRising        ListingSort = "rising"
+	SortTop           ListingSort = "top"
+	SortControversial ListingSort = "controversial"
+)
+
+// sortAcceptsTimeframe reports whether sort supports Reddit's "t" time-window
+// query parameter; hot/new/rising always operate over the live listing.
+func sortAcceptsTimeframe(sort ListingSort) bool {
+	return sort == SortTop || sort == SortControversial
+}
+
+// fetchTopPosts fetches top posts from a subreddit, using the configured
+// default sort and timeframe. It's a thin wrapper around fetchListing kept
+// for the many callers that don't need to choose a sort.
+func (c *RedditClient) fetchTopPosts(ctx context.Context, subreddit, token string) ([]RedditPost, error) {
+	return c.fetchListing(ctx, subreddit, ListingSort(AppConfig.RedditDefaultSort), AppConfig.RedditTimeFrame, defaultPostLimit, token)
+}
+
+// fetchTopPosts delegates to defaultRedditClient.
+func fetchTopPosts(ctx context.Context, subreddit, token string) ([]RedditPost, error) {
+	return defaultRedditClient.fetchTopPosts(ctx, subreddit, token)
+}
+
+// fetchListing fetches a subreddit's post listing under the given sort,
+// appending the timeframe query parameter only when sort supports it (top
+// and controversial). Posts are decoded with a pooled fastjson parser rather
+// than encoding/json, since this is the hottest path in the package. ctx is
+// honored all the way down to the outbound HTTP request, so a caller
+// canceling it (e.g. a web server draining in-flight requests at shutdown)
+// aborts the fetch instead of leaking it.
+func (c *RedditClient) fetchListing(ctx context.Context, subreddit string, sort ListingSort, timeframe string, limit int, token string) ([]RedditPost, error) {
 	if subreddit == "" {
 		return nil, fmt.Errorf("subreddit name is required")
 	}
@@ -251,10 +672,25 @@ func fetchTopPosts(subreddit, token string) ([]RedditPost, error) {
 	// Clean subreddit name (remove r/ prefix if present)
 	subreddit = strings.TrimPrefix(subreddit, "r/")
 
-	log.Printf("INFO: Fetching top %d posts from r/%s for time frame: %s", defaultPostLimit, subreddit, defaultTimeFrame)
+	ctx = withSubreddit(ctx, subreddit)
+	logger := loggerFromContext(ctx)
+
+	cacheKey := cacheKeyForListing(subreddit, sort, timeframe, limit)
+	var cachedPosts []RedditPost
+	if getCached(ctx, cacheKey, &cachedPosts) {
+		logger.Info("Serving posts from cache", "count", len(cachedPosts), "sort", sort)
+		return cachedPosts, nil
+	}
+
+	url := fmt.Sprintf("%s/r/%s/%s?limit=%d", redditBaseURL, subreddit, sort, limit)
+	if sortAcceptsTimeframe(sort) {
+		url += "&t=" + timeframe
+		logger.Info("Fetching posts", "limit", limit, "sort", sort, "timeframe", timeframe)
+	} else {
+		logger.Info("Fetching posts", "limit", limit, "sort", sort)
+	}
 
-	url := fmt.Sprintf("%s/r/%s/top?t=%s&limit=%d", redditBaseURL, subreddit, defaultTimeFrame, defaultPostLimit)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -262,32 +698,129 @@ func fetchTopPosts(subreddit, token string) ([]RedditPost, error) {
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("User-Agent", redditUserAgent)
 
-	resp, err := makeRequest(req)
+	resp, err := c.makeRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch posts: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var redditResp RedditResponse
-	if err := json.NewDecoder(resp.Body).Decode(&redditResp); err != nil {
-		return nil, fmt.Errorf("failed to decode posts response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read posts response: %w", err)
 	}
 
-	posts := make([]RedditPost, 0, len(redditResp.Data.Children))
-	for _, child := range redditResp.Data.Children {
-		posts = append(posts, child.Data)
+	parser := c.parserPool.Get()
+	defer c.parserPool.Put(parser)
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse posts response: %w", err)
+	}
+
+	rawChildren := v.GetArray("data", "children")
+	posts := make([]RedditPost, 0, len(rawChildren))
+	for _, child := range rawChildren {
+		data := child.Get("data")
+		if data == nil {
+			continue
+		}
+		posts = append(posts, RedditPost{
+			Title:     string(data.GetStringBytes("title")),
+			Ups:       data.GetInt("ups"),
+			Selftext:  string(data.GetStringBytes("selftext")),
+			Permalink: string(data.GetStringBytes("permalink")),
+		})
 	}
 
 	if len(posts) == 0 {
 		return nil, fmt.Errorf("no posts found in r/%s", subreddit)
 	}
 
-	log.Printf("INFO: Successfully fetched %d posts from r/%s", len(posts), subreddit)
+	log.Printf("INFO: Successfully fetched %d %s posts from r/%s", len(posts), sort, subreddit)
+	setCached(ctx, cacheKey, posts, AppConfig.CacheTTL)
 	return posts, nil
 }
 
-// fetchTopComments fetches top comments for a post
-func fetchTopComments(permalink, token string) ([]string, error) {
+// fetchListing delegates to defaultRedditClient.
+func fetchListing(ctx context.Context, subreddit string, sort ListingSort, timeframe string, limit int, token string) ([]RedditPost, error) {
+	return defaultRedditClient.fetchListing(ctx, subreddit, sort, timeframe, limit, token)
+}
+
+// SubredditCandidate is one match Reddit's subreddit-autocomplete endpoint
+// returned for a user's (possibly mistyped) subreddit name.
+type SubredditCandidate struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Subscribers int    `json:"subscribers"`
+}
+
+// resolveSubredditCandidates calls Reddit's subreddit_autocomplete_v2
+// endpoint to find subreddits matching query, so a caller can correct a
+// user's typo or disambiguate before fetchListing fails opaquely. Results
+// are cached under cacheKeyForSubredditResolve, since the same query is
+// cheap to look up once and reuse.
+func (c *RedditClient) resolveSubredditCandidates(ctx context.Context, query, token string) ([]SubredditCandidate, error) {
+	cacheKey := cacheKeyForSubredditResolve(query)
+	var cached []SubredditCandidate
+	if getCached(ctx, cacheKey, &cached) {
+		return cached, nil
+	}
+
+	requestURL := fmt.Sprintf("%s/api/subreddit_autocomplete_v2.json?query=%s&include_over_18=true&include_profiles=false&typeahead_active=true",
+		redditBaseURL, url.QueryEscape(query))
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	resp, err := c.makeRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve subreddit %q: %w", query, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read autocomplete response: %w", err)
+	}
+
+	parser := c.parserPool.Get()
+	defer c.parserPool.Put(parser)
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse autocomplete response: %w", err)
+	}
+
+	children := v.GetArray("data", "children")
+	candidates := make([]SubredditCandidate, 0, len(children))
+	for _, child := range children {
+		data := child.Get("data")
+		if data == nil {
+			continue
+		}
+		candidates = append(candidates, SubredditCandidate{
+			Name:        string(data.GetStringBytes("display_name")),
+			Title:       string(data.GetStringBytes("title")),
+			Subscribers: data.GetInt("subscribers"),
+		})
+	}
+
+	setCached(ctx, cacheKey, candidates, AppConfig.CacheTTL)
+	return candidates, nil
+}
+
+// resolveSubredditCandidates delegates to defaultRedditClient.
+func resolveSubredditCandidates(ctx context.Context, query, token string) ([]SubredditCandidate, error) {
+	return defaultRedditClient.resolveSubredditCandidates(ctx, query, token)
+}
+
+// fetchTopComments fetches top comments for a post. Reddit's comment-listing
+// endpoint returns a two-element array `[post listing, comment listing]`;
+// this walks it with a pooled fastjson parser instead of round-tripping
+// through []interface{}/map[string]interface{} type assertions, which is
+// both slower and far more allocation-heavy on large comment threads.
+func (c *RedditClient) fetchTopComments(ctx context.Context, permalink, token string) ([]string, error) {
 	if permalink == "" {
 		return nil, fmt.Errorf("permalink is required")
 	}
@@ -300,10 +833,17 @@ func fetchTopComments(permalink, token string) ([]string, error) {
 	// Remove trailing slash if present
 	permalink = strings.TrimSuffix(permalink, "/")
 
+	cacheKey := cacheKeyForComments(permalink)
+	var cachedComments []string
+	if getCached(ctx, cacheKey, &cachedComments) {
+		log.Printf("INFO: Serving %d comments for post %s from cache", len(cachedComments), permalink)
+		return cachedComments, nil
+	}
+
 	log.Printf("INFO: Fetching top %d comments for post: %s", defaultCommentLimit, permalink)
 
 	url := fmt.Sprintf("%s%s.json?limit=%d", redditBaseURL, permalink, defaultCommentLimit)
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -311,70 +851,273 @@ func fetchTopComments(permalink, token string) ([]string, error) {
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("User-Agent", redditUserAgent)
 
-	resp, err := makeRequest(req)
+	resp, err := c.makeRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch comments: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var commentData []interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&commentData); err != nil {
-		return nil, fmt.Errorf("failed to decode comments response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read comments response: %w", err)
 	}
 
-	if len(commentData) < 2 {
-		return nil, fmt.Errorf("unexpected comment data format")
+	comments, err := c.parseComments(body)
+	if err != nil {
+		return nil, err
 	}
 
-	// Extract comments from the second element which contains the comments
-	commentsRaw, ok := commentData[1].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid comment data format")
-	}
+	log.Printf("INFO: Successfully fetched %d comments for post: %s", len(comments), permalink)
+	setCached(ctx, cacheKey, comments, AppConfig.CacheTTL)
+	return comments, nil
+}
 
-	commentsData, ok := commentsRaw["data"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid comment data structure")
+// parseComments decodes a raw Reddit comment-listing response (the
+// `[post listing, comment listing]` two-element array) with c's pooled
+// fastjson parser. Split out of fetchTopComments so the decode path can be
+// exercised directly, e.g. by BenchmarkParseCommentsLargeThread.
+func (c *RedditClient) parseComments(body []byte) ([]string, error) {
+	parser := c.parserPool.Get()
+	defer c.parserPool.Put(parser)
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse comments response: %w", err)
 	}
 
-	children, ok := commentsData["children"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("invalid children data structure")
+	top := v.GetArray()
+	if len(top) < 2 {
+		return nil, fmt.Errorf("unexpected comment data format")
 	}
 
-	comments := make([]string, 0, len(children))
-	for _, child := range children {
-		childMap, ok := child.(map[string]interface{})
-		if !ok {
+	rawChildren := top[1].GetArray("data", "children")
+	comments := make([]string, 0, len(rawChildren))
+	for _, child := range rawChildren {
+		body := string(child.GetStringBytes("data", "body"))
+		if body == "" {
 			continue
 		}
+		comments = append(comments, body)
+	}
 
-		childData, ok := childMap["data"].(map[string]interface{})
-		if !ok {
-			continue
-		}
+	return comments, nil
+}
+
+// fetchTopComments delegates to defaultRedditClient.
+func fetchTopComments(ctx context.Context, permalink, token string) ([]string, error) {
+	return defaultRedditClient.fetchTopComments(ctx, permalink, token)
+}
 
-		body, ok := childData["body"].(string)
-		if !ok || body == "" {
+// Regexes recognizing the URL/ID forms PostIDFromURL accepts: a full
+// reddit.com permalink, a redd.it short link, and Reddit's bare "t3_<id>"
+// fullname. The short-link and fullname forms don't carry a subreddit, so
+// PostIDFromURL returns "" for subreddit in those cases.
+var (
+	redditPostURLRegexp  = regexp.MustCompile(`(?i)reddit\.com/r/([A-Za-z0-9_]+)/comments/([A-Za-z0-9]+)`)
+	redditShortURLRegexp = regexp.MustCompile(`(?i)redd\.it/([A-Za-z0-9]+)`)
+	redditFullnameRegexp = regexp.MustCompile(`(?i)^t3_([A-Za-z0-9]+)$`)
+)
+
+// PostIDFromURL recognizes a Reddit post URL or fullname and extracts the
+// subreddit (when present) and post ID, for callers that let a user paste a
+// link to one specific thread rather than naming a subreddit. ok is false
+// if s doesn't match any recognized form.
+func PostIDFromURL(s string) (subreddit, postID string, ok bool) {
+	s = strings.TrimSpace(s)
+
+	if m := redditPostURLRegexp.FindStringSubmatch(s); m != nil {
+		return m[1], m[2], true
+	}
+	if m := redditShortURLRegexp.FindStringSubmatch(s); m != nil {
+		return "", m[1], true
+	}
+	if m := redditFullnameRegexp.FindStringSubmatch(s); m != nil {
+		return "", m[1], true
+	}
+	return "", "", false
+}
+
+// fetchPostByID fetches a single post by subreddit and ID (subreddit may be
+// "" when it isn't known, e.g. from a redd.it link) along with its top
+// comments, in one call to Reddit's comments endpoint. It shares c's rate
+// limiter, typed errors, and token handling with fetchListing/fetchTopComments.
+func (c *RedditClient) fetchPostByID(ctx context.Context, subreddit, postID, token string) (RedditPost, []string, error) {
+	if postID == "" {
+		return RedditPost{}, nil, fmt.Errorf("post ID is required")
+	}
+
+	var path string
+	if subreddit != "" {
+		path = fmt.Sprintf("/r/%s/comments/%s", strings.TrimPrefix(subreddit, "r/"), postID)
+	} else {
+		path = fmt.Sprintf("/comments/%s", postID)
+	}
+
+	log.Printf("INFO: Fetching post %s", path)
+
+	url := fmt.Sprintf("%s%s.json?limit=%d", redditBaseURL, path, defaultCommentLimit)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return RedditPost{}, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("User-Agent", redditUserAgent)
+
+	resp, err := c.makeRequest(req)
+	if err != nil {
+		return RedditPost{}, nil, fmt.Errorf("failed to fetch post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return RedditPost{}, nil, fmt.Errorf("failed to read post response: %w", err)
+	}
+
+	parser := c.parserPool.Get()
+	defer c.parserPool.Put(parser)
+	v, err := parser.ParseBytes(body)
+	if err != nil {
+		return RedditPost{}, nil, fmt.Errorf("failed to parse post response: %w", err)
+	}
+
+	top := v.GetArray()
+	if len(top) < 2 {
+		return RedditPost{}, nil, fmt.Errorf("unexpected post data format")
+	}
+
+	postChildren := top[0].GetArray("data", "children")
+	if len(postChildren) == 0 {
+		return RedditPost{}, nil, fmt.Errorf("post %s not found", postID)
+	}
+	postData := postChildren[0].Get("data")
+	if postData == nil {
+		return RedditPost{}, nil, fmt.Errorf("post %s not found", postID)
+	}
+	post := RedditPost{
+		Title:     string(postData.GetStringBytes("title")),
+		Ups:       postData.GetInt("ups"),
+		Selftext:  string(postData.GetStringBytes("selftext")),
+		Permalink: string(postData.GetStringBytes("permalink")),
+	}
+
+	rawChildren := top[1].GetArray("data", "children")
+	comments := make([]string, 0, len(rawChildren))
+	for _, child := range rawChildren {
+		commentBody := string(child.GetStringBytes("data", "body"))
+		if commentBody == "" {
 			continue
 		}
+		comments = append(comments, commentBody)
+	}
 
-		comments = append(comments, body)
+	return post, comments, nil
+}
+
+// fetchPostByID delegates to defaultRedditClient.
+func fetchPostByID(ctx context.Context, subreddit, postID, token string) (RedditPost, []string, error) {
+	return defaultRedditClient.fetchPostByID(ctx, subreddit, postID, token)
+}
+
+// subredditData fetches data from a subreddit and formats it for
+// summarization. ctx is honored by every Reddit call it makes, so a caller
+// that cancels it (e.g. a web server draining in-flight requests at
+// shutdown) aborts the whole pipeline instead of letting it run to
+// completion in the background.
+func subredditData(ctx context.Context, subreddit, token string) (string, error) {
+	ctx = withSubreddit(ctx, strings.TrimPrefix(subreddit, "r/"))
+	loggerFromContext(ctx).Info("Starting data collection")
+
+	posts, err := fetchTopPosts(ctx, subreddit, token)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch posts: %w", err)
 	}
 
-	log.Printf("INFO: Successfully fetched %d comments for post: %s", len(comments), permalink)
-	return comments, nil
+	return formatPostsWithComments(ctx, subreddit, posts, token)
 }
 
-// subredditData fetches data from a subreddit and formats it for summarization
-func subredditData(subreddit, token string) (string, error) {
-	log.Printf("INFO: Starting data collection for subreddit: r/%s", strings.TrimPrefix(subreddit, "r/"))
+// subredditDataWithSort is subredditData with an explicit listing sort and
+// timeframe, for callers that let the user choose how posts are ranked
+// (e.g. "/trend sort:hot").
+func subredditDataWithSort(ctx context.Context, subreddit string, sort ListingSort, timeframe, token string) (string, error) {
+	log.Printf("INFO: Starting %s data collection for subreddit: r/%s", sort, strings.TrimPrefix(subreddit, "r/"))
 
-	posts, err := fetchTopPosts(subreddit, token)
+	posts, err := fetchListing(ctx, subreddit, sort, timeframe, defaultPostLimit, token)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch posts: %w", err)
 	}
 
+	return formatPostsWithComments(ctx, subreddit, posts, token)
+}
+
+// singlePostData fetches one post (and its top comments) by subreddit and
+// post ID and formats it for summarization, for users who want a focused
+// summary of one thread rather than a subreddit's top-N. It also returns the
+// fetched RedditPost so callers can link to it (e.g. in a Discord embed).
+func singlePostData(ctx context.Context, subreddit, postID, token string) (string, RedditPost, error) {
+	log.Printf("INFO: Starting single-post data collection for post %s", postID)
+
+	post, comments, err := fetchPostByID(ctx, subreddit, postID, token)
+	if err != nil {
+		return "", RedditPost{}, fmt.Errorf("failed to fetch post: %w", err)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# Post: %s\n", post.Title))
+	builder.WriteString(fmt.Sprintf("Upvotes: %d\n\n", post.Ups))
+
+	if post.Selftext != "" {
+		builder.WriteString(fmt.Sprintf("Content:\n%s\n\n", post.Selftext))
+	}
+
+	if len(comments) > 0 {
+		builder.WriteString("Top Comments:\n")
+		for i, comment := range comments {
+			if i >= defaultCommentLimit {
+				break
+			}
+			builder.WriteString(fmt.Sprintf("- %s\n", comment))
+		}
+	}
+
+	return builder.String(), post, nil
+}
+
+// fetchNewTopPosts fetches r/subreddit's current top posts and returns only
+// those that appear before sinceID (Permalink, used as this API's post
+// identifier) in that listing, along with the listing's newest Permalink to
+// use as the next sinceID. If sinceID is empty or not found in the listing,
+// every post fetched is treated as new.
+func fetchNewTopPosts(ctx context.Context, subreddit, token, sinceID string) (newPosts []RedditPost, newestID string, err error) {
+	posts, err := fetchTopPosts(ctx, subreddit, token)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch posts: %w", err)
+	}
+	if len(posts) == 0 {
+		return nil, sinceID, nil
+	}
+
+	newestID = posts[0].Permalink
+
+	if sinceID == "" {
+		return posts, newestID, nil
+	}
+
+	for i, post := range posts {
+		if post.Permalink == sinceID {
+			return posts[:i], newestID, nil
+		}
+	}
+
+	// sinceID has scrolled out of the current top listing; treat everything
+	// we can still see as new rather than silently dropping it.
+	return posts, newestID, nil
+}
+
+// formatPostsWithComments fetches top comments for each post (bounded
+// concurrency) and formats posts+comments into the text format summarizePosts
+// expects.
+func formatPostsWithComments(ctx context.Context, subreddit string, posts []RedditPost, token string) (string, error) {
 	var builder strings.Builder
 	cleanSubredditName := strings.TrimPrefix(subreddit, "r/")
 	builder.WriteString(fmt.Sprintf("# Top posts from r/%s\n\n", cleanSubredditName))
@@ -398,7 +1141,7 @@ func subredditData(subreddit, token string) (string, error) {
 			defer func() { <-semaphore }()
 
 			log.Printf("INFO: Processing post %d: %s", i+1, post.Title)
-			comments, err := fetchTopComments(post.Permalink, token)
+			comments, err := fetchTopComments(ctx, post.Permalink, token)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to fetch comments for post %d: %w", i, err)
 				return
@@ -1,16 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
 
-func TestSaveReadTokenFile(t *testing.T) {
+func TestFileTokenStore(t *testing.T) {
 	dir := t.TempDir()
 	file := filepath.Join(dir, "token.json")
 
@@ -19,40 +21,44 @@ func TestSaveReadTokenFile(t *testing.T) {
 	withEnv(t, "REDDIT_TOKEN_EXPIRY_BUFFER", "0s")
 	LoadConfig()
 
-	if err := saveTokenToFile("token123", 2); err != nil {
-		t.Fatalf("saveTokenToFile failed: %v", err)
+	store := newFileTokenStore(AppConfig.RedditTokenFilePath)
+	ctx := context.Background()
+
+	if err := store.Set(ctx, TokenData{AccessToken: "token123", ExpiresAt: time.Now().Add(2 * time.Second)}); err != nil {
+		t.Fatalf("Set failed: %v", err)
 	}
 
-	token, err := readTokenFromFile()
+	tokenData, err := store.Get(ctx)
 	if err != nil {
-		t.Fatalf("readTokenFromFile failed: %v", err)
+		t.Fatalf("Get failed: %v", err)
 	}
-	if token != "token123" {
-		t.Fatalf("unexpected token: %s", token)
+	if tokenData.AccessToken != "token123" {
+		t.Fatalf("unexpected token: %s", tokenData.AccessToken)
 	}
 
-	// After expiry buffer passes, token should be considered invalid
-	// Overwrite with an expiring token
-	if err := saveTokenToFile("short", 0); err != nil {
-		t.Fatalf("saveTokenToFile (short) failed: %v", err)
+	// Overwrite with an already-expired token
+	if err := store.Set(ctx, TokenData{AccessToken: "short", ExpiresAt: time.Now()}); err != nil {
+		t.Fatalf("Set (short) failed: %v", err)
 	}
 	// Wait a tick to ensure time comparison passes
 	time.Sleep(2 * time.Millisecond)
-	token, err = readTokenFromFile()
+	tokenData, err = store.Get(ctx)
 	if err != nil {
-		t.Fatalf("readTokenFromFile short failed: %v", err)
+		t.Fatalf("Get (short) failed: %v", err)
 	}
-	if token != "" {
-		t.Fatalf("expected empty token due to expiry, got: %s", token)
+	if time.Now().Add(tokenExpiryBuffer).Before(tokenData.ExpiresAt) {
+		t.Fatalf("expected expired token, got expiry: %v", tokenData.ExpiresAt)
 	}
 
-	// ensure restrictive perms on file
-	info, err := os.Stat(file)
+	if err := store.Delete(ctx); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	tokenData, err = store.Get(ctx)
 	if err != nil {
-		t.Fatalf("stat failed: %v", err)
+		t.Fatalf("Get after Delete failed: %v", err)
 	}
-	if perm := info.Mode().Perm(); perm != 0o600 {
-		t.Fatalf("unexpected perms: %v", perm)
+	if tokenData.AccessToken != "" {
+		t.Fatalf("expected empty token after Delete, got: %s", tokenData.AccessToken)
 	}
 }
 
@@ -67,14 +73,11 @@ func TestRedditHTTPFlow(t *testing.T) {
 	})
 	mux.HandleFunc("/r/test/top", func(w http.ResponseWriter, r *http.Request) {
 		// return 2 posts
-		resp := RedditResponse{}
-		resp.Data.Children = []struct {
-			Data RedditPost "json:\"data\""
-		}{
-			{Data: RedditPost{Title: "P1", Ups: 10, Selftext: "", Permalink: "/r/test/comments/aa/slug"}},
-			{Data: RedditPost{Title: "P2", Ups: 5, Selftext: "Body", Permalink: "/r/test/comments/bb/slug"}},
-		}
-		_ = json.NewEncoder(w).Encode(resp)
+		payload := map[string]any{"data": map[string]any{"children": []any{
+			map[string]any{"data": map[string]any{"title": "P1", "ups": 10, "selftext": "", "permalink": "/r/test/comments/aa/slug"}},
+			map[string]any{"data": map[string]any{"title": "P2", "ups": 5, "selftext": "Body", "permalink": "/r/test/comments/bb/slug"}},
+		}}}
+		_ = json.NewEncoder(w).Encode(payload)
 	})
 	mux.HandleFunc("/r/test/comments/aa/slug.json", func(w http.ResponseWriter, r *http.Request) {
 		// Reddit comments shape: an array where second element holds comments
@@ -115,8 +118,9 @@ func TestRedditHTTPFlow(t *testing.T) {
 	withEnv(t, "REDDIT_TOKEN_EXPIRY_BUFFER", "0s")
 	withEnv(t, "REDDIT_CLIENT_ID", "id")
 	withEnv(t, "REDDIT_CLIENT_SECRET", "secret")
+	withEnv(t, "REDDIT_TOKEN_FILE_PATH", filepath.Join(t.TempDir(), "token.json"))
 	LoadConfig()
-	InitializeRedditRateLimiter()
+	InitializeTokenStore()
 
 	// First, get token via flow to ensure code path executes
 	tok, err := getRedditAccessToken()
@@ -143,3 +147,118 @@ func TestRedditHTTPFlow(t *testing.T) {
 		t.Fatalf("expected renewed token: err=%v tok2=%q", err, tok2)
 	}
 }
+
+// TestMakeRequestHeaderRateLimit stubs Reddit's x-ratelimit-* headers and
+// asserts makeRequest pauses once the reported remaining budget drops below
+// RequestRemainingBuffer, until the reported reset window elapses.
+func TestMakeRequestHeaderRateLimit(t *testing.T) {
+	client := NewRedditClient(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/low", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ratelimit-remaining", "1")
+		w.Header().Set("x-ratelimit-used", "599")
+		w.Header().Set("x-ratelimit-reset", "1")
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	req, err := http.NewRequest("GET", srv.URL+"/low", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	if _, err := client.makeRequest(req); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+
+	req2, err := http.NewRequest("GET", srv.URL+"/low", nil)
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	start := time.Now()
+	if _, err := client.makeRequest(req2); err != nil {
+		t.Fatalf("second request failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("expected makeRequest to pause for the reported reset window, only waited %s", elapsed)
+	}
+}
+
+// TestMakeRequestErrorClassification asserts makeRequest maps Reddit's
+// common failure status codes to the typed errors subredditData's callers
+// rely on.
+func TestMakeRequestErrorClassification(t *testing.T) {
+	client := NewRedditClient(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) })
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusForbidden) })
+	mux.HandleFunc("/unauth", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) })
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	cases := []struct {
+		path    string
+		wantErr error
+	}{
+		{"/missing", ErrSubredditNotFound},
+		{"/private", ErrSubredditPrivate},
+		{"/unauth", ErrOauthRevoked},
+	}
+	for _, tc := range cases {
+		req, err := http.NewRequest("GET", srv.URL+tc.path, nil)
+		if err != nil {
+			t.Fatalf("failed to build request for %s: %v", tc.path, err)
+		}
+		if _, err := client.makeRequest(req); !errors.Is(err, tc.wantErr) {
+			t.Fatalf("%s: expected error wrapping %v, got %v", tc.path, tc.wantErr, err)
+		}
+	}
+}
+
+// buildLargeCommentListing builds a synthetic Reddit comment-listing payload
+// (the `[post listing, comment listing]` shape) sized around 1MB, roughly
+// what a heavily-discussed thread's raw JSON looks like.
+func buildLargeCommentListing(n int) []byte {
+	body := strings.Repeat("This is a fairly typical Reddit comment with some opinions and a link. ", 7)
+
+	var children []any
+	for i := 0; i < n; i++ {
+		children = append(children, map[string]any{"data": map[string]any{"body": body}})
+	}
+
+	payload := []any{
+		map[string]any{},
+		map[string]any{"data": map[string]any{"children": children}},
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
+// BenchmarkParseCommentsLargeThread measures allocations of the fastjson
+// comment decoder against a ~1MB comment listing, the workload that
+// motivated moving off encoding/json's []interface{}/map[string]interface{}
+// decoding.
+func BenchmarkParseCommentsLargeThread(b *testing.B) {
+	body := buildLargeCommentListing(2000)
+	b.Logf("payload size: %d bytes", len(body))
+
+	client := NewRedditClient(nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		comments, err := client.parseComments(body)
+		if err != nil {
+			b.Fatalf("parseComments failed: %v", err)
+		}
+		if len(comments) != 2000 {
+			b.Fatalf("expected 2000 comments, got %d", len(comments))
+		}
+	}
+}
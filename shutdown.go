@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// ShutdownParticipant is a named, shutdown-bound dependency: Stop is called
+// once the process starts shutting down, and must respect ctx's deadline
+// rather than running to completion unconditionally.
+type ShutdownParticipant interface {
+	Stop(ctx context.Context) error
+}
+
+// shutdownParticipantFunc adapts a plain function to ShutdownParticipant, so
+// callers can RegisterFunc a closure instead of defining a named type.
+type shutdownParticipantFunc func(ctx context.Context) error
+
+func (f shutdownParticipantFunc) Stop(ctx context.Context) error { return f(ctx) }
+
+// ShutdownManager coordinates graceful process shutdown: participants
+// register themselves once at startup, and Shutdown stops them in
+// registration order, bounded by ctx's deadline, logging which ones (if
+// any) failed to stop cleanly instead of letting one straggler hide the
+// rest. Registration order matters: register the things that should stop
+// first (e.g. "stop accepting new work") ahead of the things that depend on
+// them having stopped (e.g. "drain the work already in flight").
+type ShutdownManager struct {
+	mutex    sync.Mutex
+	names    []string
+	stoppers []ShutdownParticipant
+}
+
+// NewShutdownManager creates an empty ShutdownManager.
+func NewShutdownManager() *ShutdownManager {
+	return &ShutdownManager{}
+}
+
+// Register adds a named participant to be stopped on shutdown. name is used
+// only for logging which participants failed to stop in time.
+func (m *ShutdownManager) Register(name string, participant ShutdownParticipant) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.names = append(m.names, name)
+	m.stoppers = append(m.stoppers, participant)
+}
+
+// RegisterFunc is Register for a plain stop function.
+func (m *ShutdownManager) RegisterFunc(name string, stop func(ctx context.Context) error) {
+	m.Register(name, shutdownParticipantFunc(stop))
+}
+
+// Shutdown stops every registered participant in registration order,
+// collectively bounded by ctx's deadline. A participant that errors or
+// overruns ctx doesn't stop the rest from getting their turn; every
+// failure is logged, and Shutdown returns a single error naming all of
+// them once every participant has had a chance to stop.
+func (m *ShutdownManager) Shutdown(ctx context.Context) error {
+	m.mutex.Lock()
+	names := append([]string(nil), m.names...)
+	stoppers := append([]ShutdownParticipant(nil), m.stoppers...)
+	m.mutex.Unlock()
+
+	logger := shutdownLogger()
+	logger.Info("Shutdown starting", "participants", len(stoppers))
+
+	var failed []string
+	for i, participant := range stoppers {
+		if err := participant.Stop(ctx); err != nil {
+			failed = append(failed, names[i])
+			logger.Error("Shutdown participant failed to stop cleanly", "participant", names[i], "error", err)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("shutdown participants failed to stop cleanly: %s", strings.Join(failed, ", "))
+	}
+
+	logger.Info("Shutdown complete")
+	return nil
+}
+
+// shutdownLogger returns AppLogger, falling back to slog.Default() for
+// tests or any caller that runs before InitializeLogger.
+func shutdownLogger() *slog.Logger {
+	if AppLogger != nil {
+		return AppLogger
+	}
+	return slog.Default()
+}
+
+// WaitForSignal blocks until a SIGINT or SIGTERM arrives or ctx is done,
+// whichever comes first.
+func WaitForSignal(ctx context.Context) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signalChan)
+
+	select {
+	case <-signalChan:
+	case <-ctx.Done():
+	}
+}
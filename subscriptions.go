@@ -0,0 +1,319 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// subscriptionsFilePath is where recurring /subscribe entries are persisted.
+var subscriptionsFilePath = filepath.Join("data", "subscriptions.json")
+
+// Subscription is a recurring /trend run registered via /subscribe.
+type Subscription struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	ChannelID string    `json:"channel_id"`
+	GuildID   string    `json:"guild_id"`
+	Subreddit string    `json:"subreddit"`
+	Schedule  string    `json:"schedule"` // "daily", "weekly", or a duration like "6h"
+	Model     string    `json:"model"`
+	LastRun   time.Time `json:"last_run"`
+	NextRun   time.Time `json:"next_run"`
+}
+
+// scheduleInterval resolves a Schedule string to a fixed interval. "daily"
+// and "weekly" are conveniences; anything else is parsed as a duration.
+func scheduleInterval(schedule string) (time.Duration, error) {
+	switch strings.ToLower(schedule) {
+	case "daily":
+		return 24 * time.Hour, nil
+	case "weekly":
+		return 7 * 24 * time.Hour, nil
+	default:
+		d, err := time.ParseDuration(schedule)
+		if err != nil {
+			return 0, fmt.Errorf("invalid schedule %q: %w", schedule, err)
+		}
+		return d, nil
+	}
+}
+
+// SubscriptionScheduler runs registered subscriptions in-process, delivering
+// a fresh /trend analysis to the original channel (or the user's DM if the
+// channel is gone) on each fire.
+type SubscriptionScheduler struct {
+	bot  *DiscordBot
+	mu   sync.Mutex
+	subs map[string]*Subscription
+}
+
+// NewSubscriptionScheduler creates a scheduler and loads persisted
+// subscriptions from data/subscriptions.json.
+func NewSubscriptionScheduler(bot *DiscordBot) *SubscriptionScheduler {
+	s := &SubscriptionScheduler{bot: bot, subs: make(map[string]*Subscription)}
+
+	var persisted map[string]*Subscription
+	if err := ReadJSONFile(subscriptionsFilePath, &persisted); err != nil {
+		log.Printf("WARNING: Failed to read subscriptions: %v", err)
+	} else {
+		for id, sub := range persisted {
+			s.subs[id] = sub
+		}
+	}
+
+	return s
+}
+
+// Add registers a new subscription and persists it.
+func (s *SubscriptionScheduler) Add(sub *Subscription) {
+	s.mu.Lock()
+	s.subs[sub.ID] = sub
+	s.mu.Unlock()
+	s.save()
+}
+
+// Remove deletes a subscription owned by userID, returning false if it
+// doesn't exist or belongs to someone else.
+func (s *SubscriptionScheduler) Remove(id, userID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.subs[id]
+	if !ok || sub.UserID != userID {
+		return false
+	}
+	delete(s.subs, id)
+	s.save()
+	return true
+}
+
+// List returns every subscription owned by userID.
+func (s *SubscriptionScheduler) List(userID string) []*Subscription {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []*Subscription
+	for _, sub := range s.subs {
+		if sub.UserID == userID {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
+// save persists all subscriptions to data/subscriptions.json.
+func (s *SubscriptionScheduler) save() {
+	s.mu.Lock()
+	snapshot := make(map[string]*Subscription, len(s.subs))
+	for id, sub := range s.subs {
+		snapshot[id] = sub
+	}
+	s.mu.Unlock()
+
+	if err := WriteJSONFile(subscriptionsFilePath, snapshot); err != nil {
+		log.Printf("ERROR: Failed to persist subscriptions: %v", err)
+	}
+}
+
+// Run ticks the scheduler until stopChan is closed, firing any subscription
+// whose NextRun has passed.
+func (s *SubscriptionScheduler) Run(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			log.Println("Subscription scheduler stopped")
+			return
+		case <-ticker.C:
+			s.fireDue()
+		}
+	}
+}
+
+// fireDue runs every subscription whose NextRun has passed, rescheduling it
+// for its next interval.
+func (s *SubscriptionScheduler) fireDue() {
+	now := time.Now()
+
+	s.mu.Lock()
+	var due []*Subscription
+	for _, sub := range s.subs {
+		if !sub.NextRun.IsZero() && now.After(sub.NextRun) {
+			due = append(due, sub)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, sub := range due {
+		interval, err := scheduleInterval(sub.Schedule)
+		if err != nil {
+			log.Printf("ERROR: Subscription %s has invalid schedule: %v", sub.ID, err)
+			continue
+		}
+
+		go s.deliver(sub)
+
+		s.mu.Lock()
+		sub.LastRun = now
+		sub.NextRun = now.Add(interval)
+		s.mu.Unlock()
+	}
+	s.save()
+}
+
+// deliver runs the existing trend analysis path and posts it to the
+// subscription's channel, falling back to a DM if the channel is gone.
+func (s *SubscriptionScheduler) deliver(sub *Subscription) {
+	session := s.bot.session
+
+	channelID := sub.ChannelID
+	if _, err := session.Channel(channelID); err != nil {
+		dmChannel, dmErr := session.UserChannelCreate(sub.UserID)
+		if dmErr != nil {
+			log.Printf("ERROR: Subscription %s: channel gone and DM failed: %v", sub.ID, dmErr)
+			return
+		}
+		channelID = dmChannel.ID
+	}
+
+	log.Printf("INFO: Firing subscription %s for r/%s", sub.ID, sub.Subreddit)
+	s.bot.handleTrendAnalysis(session, channelID, sub.UserID, sub.Subreddit, AppConfig.RedditDefaultSort, AppConfig.RedditTimeFrame)
+}
+
+// subscribeCommand is the /subscribe ApplicationCommand definition.
+var subscribeCommand = &discordgo.ApplicationCommand{
+	Name:        "subscribe",
+	Description: "Get a recurring trend digest for a subreddit",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "subreddit",
+			Description: "The subreddit to watch (without r/)",
+			Required:    true,
+		},
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "schedule",
+			Description: "daily, weekly, or a duration like 6h",
+			Required:    true,
+		},
+	},
+}
+
+var unsubscribeCommand = &discordgo.ApplicationCommand{
+	Name:        "unsubscribe",
+	Description: "Cancel a recurring trend digest",
+	Options: []*discordgo.ApplicationCommandOption{
+		{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "id",
+			Description: "Subscription ID from /subscriptions",
+			Required:    true,
+		},
+	},
+}
+
+var subscriptionsCommand = &discordgo.ApplicationCommand{
+	Name:        "subscriptions",
+	Description: "List your recurring trend digests",
+}
+
+// handleSubscribeSlashCommand handles the /subscribe command.
+func (bot *DiscordBot) handleSubscribeSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) < 2 {
+		bot.respondError(s, i, "Please provide a subreddit and a schedule")
+		return
+	}
+
+	subreddit := strings.TrimPrefix(options[0].StringValue(), "r/")
+	schedule := options[1].StringValue()
+	userID := i.Member.User.ID
+
+	interval, err := scheduleInterval(schedule)
+	if err != nil {
+		bot.respondError(s, i, err.Error())
+		return
+	}
+
+	sub := &Subscription{
+		ID:        fmt.Sprintf("%s-%s-%d", userID, subreddit, time.Now().UnixNano()),
+		UserID:    userID,
+		ChannelID: i.ChannelID,
+		GuildID:   i.GuildID,
+		Subreddit: subreddit,
+		Schedule:  schedule,
+		Model:     bot.getUserSession(userID).Model,
+		NextRun:   time.Now().Add(interval),
+	}
+	bot.scheduler.Add(sub)
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("✅ Subscribed to r/%s (%s). ID: `%s`", subreddit, schedule, sub.ID),
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding to subscribe interaction: %v", err)
+	}
+}
+
+// handleUnsubscribeSlashCommand handles the /unsubscribe command.
+func (bot *DiscordBot) handleUnsubscribeSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		bot.respondError(s, i, "Please provide a subscription ID")
+		return
+	}
+
+	id := options[0].StringValue()
+	userID := i.Member.User.ID
+
+	content := fmt.Sprintf("✅ Unsubscribed from `%s`", id)
+	if !bot.scheduler.Remove(id, userID) {
+		content = fmt.Sprintf("❌ No subscription `%s` found for you", id)
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: content},
+	})
+	if err != nil {
+		log.Printf("Error responding to unsubscribe interaction: %v", err)
+	}
+}
+
+// handleSubscriptionsSlashCommand handles the /subscriptions command.
+func (bot *DiscordBot) handleSubscriptionsSlashCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	subs := bot.scheduler.List(userID)
+
+	var content string
+	if len(subs) == 0 {
+		content = "📝 You have no active subscriptions. Use `/subscribe` to start one!"
+	} else {
+		var builder strings.Builder
+		builder.WriteString("📝 **Your Subscriptions**\n\n")
+		for _, sub := range subs {
+			builder.WriteString(fmt.Sprintf("• `%s` — r/%s (%s), next run %s\n", sub.ID, sub.Subreddit, sub.Schedule, sub.NextRun.Format(time.RFC822)))
+		}
+		content = builder.String()
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: content},
+	})
+	if err != nil {
+		log.Printf("Error responding to subscriptions interaction: %v", err)
+	}
+}
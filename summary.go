@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SummaryItem is one entry in a structured summary section: an emoji
+// indicator, a short title, and the body text describing it.
+type SummaryItem struct {
+	Emoji string `json:"emoji"`
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// SummaryResult is the structured shape the OpenAI-compatible backend is
+// asked to return via ChatCompletionRequest.ResponseFormat, replacing the
+// old freeform-text-then-regex-replace approach. It's rendered to Markdown
+// for Discord/Telegram via Markdown(), and returned as-is alongside the
+// rendered Markdown so the web UI can render proper cards instead.
+type SummaryResult struct {
+	TrendingTopics []SummaryItem `json:"trending_topics"`
+	CommunityPulse SummaryItem   `json:"community_pulse"`
+	HotTakes       []SummaryItem `json:"hot_takes"`
+}
+
+// summaryItemSchema is the JSON Schema for a single SummaryItem, reused for
+// trending_topics entries, hot_takes entries, and community_pulse itself.
+var summaryItemSchema = map[string]interface{}{
+	"type": "object",
+	"properties": map[string]interface{}{
+		"emoji": map[string]interface{}{"type": "string"},
+		"title": map[string]interface{}{"type": "string"},
+		"body":  map[string]interface{}{"type": "string"},
+	},
+	"required":             []string{"emoji", "title", "body"},
+	"additionalProperties": false,
+}
+
+// summaryResponseFormat is the response_format sent with chat-completion
+// requests to constrain the model's output to the SummaryResult JSON shape.
+var summaryResponseFormat = &ResponseFormat{
+	Type: "json_schema",
+	JSONSchema: &JSONSchemaSpec{
+		Name:   "reddit_summary",
+		Strict: true,
+		Schema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"trending_topics": map[string]interface{}{
+					"type":  "array",
+					"items": summaryItemSchema,
+				},
+				"community_pulse": summaryItemSchema,
+				"hot_takes": map[string]interface{}{
+					"type":  "array",
+					"items": summaryItemSchema,
+				},
+			},
+			"required":             []string{"trending_topics", "community_pulse", "hot_takes"},
+			"additionalProperties": false,
+		},
+	},
+}
+
+// Markdown renders r in the same TRENDING TOPICS/COMMUNITY PULSE/HOT TAKES
+// layout Discord and Telegram have always shown, built from structured data
+// instead of a regex-replaced freeform response.
+func (r SummaryResult) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("📊 *TRENDING TOPICS*\n")
+	for _, topic := range r.TrendingTopics {
+		fmt.Fprintf(&b, "- %s *%s*: %s\n", topic.Emoji, topic.Title, topic.Body)
+	}
+
+	fmt.Fprintf(&b, "\n💬 *COMMUNITY PULSE*\n%s %s: %s\n", r.CommunityPulse.Emoji, r.CommunityPulse.Title, r.CommunityPulse.Body)
+
+	b.WriteString("\n🔥 *HOT TAKES*\n")
+	for _, take := range r.HotTakes {
+		fmt.Fprintf(&b, "- %s *%s*: %s\n", take.Emoji, take.Title, take.Body)
+	}
+
+	return b.String()
+}
+
+// parseSummaryResultText parses content, the raw text of a chat-completion
+// choice, as a SummaryResult.
+func parseSummaryResultText(content string) (*SummaryResult, error) {
+	if content == "" {
+		return nil, fmt.Errorf("empty text in response content")
+	}
+	var result SummaryResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse structured summary: %w", err)
+	}
+	return &result, nil
+}
+
+// parseSummaryResult extracts and parses the structured summary from an
+// OpenAI-compatible chat-completion response.
+func parseSummaryResult(response *ChatCompletionResponse) (*SummaryResult, error) {
+	if response == nil || len(response.Choices) == 0 {
+		return nil, fmt.Errorf("empty content in response")
+	}
+	return parseSummaryResultText(response.Choices[0].Message.Content)
+}
+
+// formatStructuredResponse parses response into a SummaryResult and renders
+// it as Markdown prefixed with the configured summary header, returning both
+// so callers that can use the richer structured data (e.g. the web UI)
+// aren't limited to the rendered string.
+func formatStructuredResponse(response *ChatCompletionResponse) (string, *SummaryResult, error) {
+	result, err := parseSummaryResult(response)
+	if err != nil {
+		return "", nil, err
+	}
+	return AppConfig.SummaryHeader + result.Markdown(), result, nil
+}
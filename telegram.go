@@ -2,9 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -35,6 +36,10 @@ func ErrInvalidEnvVar(varName string, err error) error {
 	return EnvVarError{VarName: varName, Err: err}
 }
 
+// historyWriterBufferSize bounds how many completed analyses can be queued
+// for the history writer before Enqueue blocks the caller.
+const historyWriterBufferSize = 32
+
 // UserRequest represents a user request to the bot
 type UserRequest struct {
 	UserID    int64
@@ -43,48 +48,38 @@ type UserRequest struct {
 	Timestamp time.Time
 }
 
-// ModelInfo represents information about an available model
-type ModelInfo struct {
-	Codename    string
-	Name        string
-	Description string
-}
-
 // Bot represents a Telegram bot with its API client and configuration
 type Bot struct {
 	api             *tgbotapi.BotAPI
-	logger          *log.Logger
+	logger          *slog.Logger
 	config          *Config
 	stopChan        chan struct{}
 	wg              sync.WaitGroup
+	msgWg           sync.WaitGroup
 	historyFilePath string
 
-	// History of user requests (unique subreddit names)
-	historyMutex sync.RWMutex
-	history      []string
-
-	// Model selection
-	modelMutex sync.RWMutex
-	model      string
-}
-
-// Available models for selection
-var availableModels = []ModelInfo{
-	{
-		Codename:    "simple",
-		Name:        "claude-3-haiku-20240307",
-		Description: "Fast and efficient model (default)",
-	},
-	{
-		Codename:    "balanced",
-		Name:        "claude-3-sonnet-20240229",
-		Description: "Balanced performance and capabilities",
-	},
-	{
-		Codename:    "advanced",
-		Name:        "claude-3-opus-20240229",
-		Description: "Most capable model for complex tasks",
-	},
+	// historyStore persists full analysis records (subreddit, summary,
+	// model, agent) behind /recent, /search, /resummarize, and the legacy
+	// /history and /clearhistory commands.
+	historyStore ChatHistoryStore
+
+	// historyWriter serializes writes to historyStore through a single
+	// goroutine, so analyzeSubreddit never blocks on the store and
+	// shutdown can drain it instead of racing a write goroutine.
+	historyWriter *historyWriter
+
+	// Agent selection. agents is loaded once at startup from
+	// config.AgentsFilePath (or the built-in defaults); agentMutex guards
+	// the user's current pick, analogous to the old global model setting.
+	agents       []Agent
+	agentMutex   sync.RWMutex
+	currentAgent string
+
+	// watcherStore and watcherScheduler back /watch, /watchers, and /unwatch,
+	// delivering a fresh agent summary on a schedule without the user having
+	// to ask again.
+	watcherStore     *TelegramWatcherStore
+	watcherScheduler *TelegramWatcherScheduler
 }
 
 // NewBot creates a new Bot instance with the provided configuration
@@ -95,89 +90,61 @@ func NewBot(config *Config) (*Bot, error) {
 	}
 
 	api.Debug = config.Debug
-	logger := log.New(os.Stdout, "TelegramBot: ", log.LstdFlags)
+	logger := AppLogger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger = logger.With("component", "telegram_bot")
+
+	agents := LoadAgents(config.AgentsFilePath)
 
 	bot := &Bot{
 		api:             api,
 		logger:          logger,
 		config:          config,
 		stopChan:        make(chan struct{}),
-		history:         make([]string, 0, 50), // Initialize history with capacity for 50 items
-		model:           config.AnthropicModel, // Initialize model from config
+		agents:          agents,
+		currentAgent:    agents[0].Codename,
 		historyFilePath: config.HistoryFilePath,
 	}
 
-	// Load history from file if it exists
-	if err := bot.loadHistoryFromFile(); err != nil {
-		logger.Printf("Failed to load history from file: %v. Starting with empty history.", err)
-	}
-
-	return bot, nil
-}
-
-// loadHistoryFromFile loads the subreddit history from a file
-func (b *Bot) loadHistoryFromFile() error {
-	// Check if file exists
-	if _, err := os.Stat(b.historyFilePath); os.IsNotExist(err) {
-		// File doesn't exist, which is fine for a new instance
-		return nil
-	}
-
-	// Read the file
-	data, err := os.ReadFile(b.historyFilePath)
+	historyStore, err := NewChatHistoryStore(config)
 	if err != nil {
-		return fmt.Errorf("failed to read history file: %w", err)
+		return nil, fmt.Errorf("failed to initialize chat history store: %w", err)
 	}
+	bot.historyStore = historyStore
 
-	// Split by lines and filter empty lines
-	lines := strings.Split(string(data), "\n")
-	var subreddits []string
-	for _, line := range lines {
-		if strings.TrimSpace(line) != "" {
-			subreddits = append(subreddits, line)
-		}
+	if err := migrateLegacyHistoryFile(historyStore, config.HistoryFilePath, config.AuthorizedUserID, ""); err != nil {
+		logger.Warn("Failed to migrate legacy history file", "error", err)
 	}
 
-	// Update history
-	b.historyMutex.Lock()
-	defer b.historyMutex.Unlock()
-	b.history = subreddits
-
-	b.logger.Printf("Loaded %d subreddits from history file", len(subreddits))
-	return nil
-}
-
-// saveHistoryToFile saves the subreddit history to a file
-func (b *Bot) saveHistoryToFile() error {
-	b.historyMutex.RLock()
-	defer b.historyMutex.RUnlock()
-
-	// Create the file content
-	content := strings.Join(b.history, "\n")
+	bot.historyWriter = newHistoryWriter(historyStore, historyWriterBufferSize)
 
-	// Write to file
-	err := os.WriteFile(b.historyFilePath, []byte(content), 0644)
+	watcherStore, err := NewTelegramWatcherStore(config.TelegramWatcherFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to write history file: %w", err)
+		return nil, fmt.Errorf("failed to initialize telegram watcher store: %w", err)
 	}
+	bot.watcherStore = watcherStore
+	bot.watcherScheduler = NewTelegramWatcherScheduler(bot, watcherStore)
 
-	b.logger.Printf("Saved %d subreddits to history file", len(b.history))
-	return nil
+	return bot, nil
 }
 
 // Start begins the bot's update processing loop
 func (b *Bot) Start(ctx context.Context) error {
-	b.logger.Println("Bot starting...")
+	b.logger.Info("Bot starting...")
 
 	updateConfig := tgbotapi.NewUpdate(0)
 	updateConfig.Timeout = 30
 	updates := b.api.GetUpdatesChan(updateConfig)
 
-	b.logger.Println("Bot started successfully")
+	b.logger.Info("Bot started successfully")
 
 	b.wg.Add(1)
 	defer b.wg.Done()
 
+	go b.watcherScheduler.Run(b.stopChan)
+
 	for {
 		select {
 		case update, ok := <-updates:
@@ -186,119 +153,326 @@ func (b *Bot) Start(ctx context.Context) error {
 			}
 
 			if update.Message != nil {
-				if err := b.handleMessage(update.Message); err != nil {
-					b.logger.Printf("Error handling message: %v", err)
-				}
+				reqCtx := withRequestContext(ctx, update.Message.From.ID)
+				message := update.Message
+				b.msgWg.Add(1)
+				go func() {
+					defer b.msgWg.Done()
+					if err := b.handleMessage(reqCtx, message); err != nil {
+						loggerFromContext(reqCtx).Error("Error handling message", "error", err)
+					}
+				}()
+			}
+			if update.CallbackQuery != nil {
+				reqCtx := withRequestContext(ctx, update.CallbackQuery.From.ID)
+				callbackQuery := update.CallbackQuery
+				b.msgWg.Add(1)
+				go func() {
+					defer b.msgWg.Done()
+					if err := b.handleCallbackQuery(reqCtx, callbackQuery); err != nil {
+						loggerFromContext(reqCtx).Error("Error handling callback query", "error", err)
+					}
+				}()
 			}
 		case <-ctx.Done():
-			b.logger.Println("Context canceled, stopping bot...")
+			b.logger.Info("Context canceled, stopping bot...")
 			return ctx.Err()
 		case <-b.stopChan:
-			b.logger.Println("Stop signal received, stopping bot...")
+			b.logger.Info("Stop signal received, stopping bot...")
 			return nil
 		}
 	}
 }
 
-// Stop gracefully stops the bot
+// Stop gracefully stops the bot. Participants are registered in the order
+// they must stop: first the update loop (so no new messages arrive), then
+// the in-flight handleMessage/handleCallbackQuery calls already dispatched,
+// then the history writer (which those calls may have just enqueued into),
+// and finally the underlying history store. Every participant gets a
+// chance to stop even if an earlier one times out; Stop logs and returns a
+// single error naming whichever ones didn't.
 func (b *Bot) Stop(ctx context.Context) error {
-	b.logger.Println("Stopping bot...")
+	b.logger.Info("Stopping bot...")
 
-	// Save history to file before stopping
-	if err := b.saveHistoryToFile(); err != nil {
-		b.logger.Printf("Error saving history to file: %v", err)
-	}
+	manager := NewShutdownManager()
+
+	manager.RegisterFunc("update_loop", func(ctx context.Context) error {
+		close(b.stopChan)
+		done := make(chan struct{})
+		go func() {
+			b.wg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("update loop did not stop: %w", ctx.Err())
+		}
+	})
+
+	manager.RegisterFunc("in_flight_messages", func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			b.msgWg.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return fmt.Errorf("in-flight messages did not finish: %w", ctx.Err())
+		}
+	})
 
-	// Signal the bot to stop
-	close(b.stopChan)
+	manager.RegisterFunc("history_writer", b.historyWriter.Stop)
 
-	// Wait for the bot to stop with a timeout
-	done := make(chan struct{})
-	go func() {
-		b.wg.Wait()
-		close(done)
-	}()
+	manager.RegisterFunc("history_store", func(ctx context.Context) error {
+		return b.historyStore.Close()
+	})
 
-	select {
-	case <-done:
-		b.logger.Println("Bot stopped successfully")
-		return nil
-	case <-ctx.Done():
-		return fmt.Errorf("timeout waiting for bot to stop: %w", ctx.Err())
+	if err := manager.Shutdown(ctx); err != nil {
+		return err
 	}
+
+	b.logger.Info("Bot stopped successfully")
+	return nil
 }
 
-func (b *Bot) handleMessage(message *tgbotapi.Message) error {
+// handleMessage is the request-scoped entry point for an incoming Telegram
+// message; ctx carries the request ID and user ID stamped by Start so every
+// log line emitted while handling it, down through subredditData and
+// summarizeWithAgent, can be correlated back to this one interaction.
+func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) error {
 	// Check if user is authorized
 	if message.From.ID != b.config.AuthorizedUserID {
-		reply := tgbotapi.NewMessage(message.Chat.ID, "‚õî Sorry, you're not authorized to use this bot.")
+		reply := tgbotapi.NewMessage(message.Chat.ID, "⛔ Sorry, you're not authorized to use this bot.")
 		_, err := b.api.Send(reply)
 		return err
 	}
 
-	// Save the request to history
-	b.saveToHistory(message)
-
 	// Handle commands
 	if message.IsCommand() {
-		return b.handleCommand(message)
+		return b.handleCommand(ctx, message)
 	}
 
-	// Handle regular message (subreddit name)
-	subredditName := message.Text
+	// Handle regular message (subreddit name, optionally prefixed with an
+	// agent codename like "news-brief r/worldnews" to route this one
+	// request to a specific agent without changing the global pick).
+	agent, query := b.parseAgentPrefix(strings.TrimSpace(message.Text))
+	query = strings.TrimPrefix(query, "r/")
+	ctx = withSubreddit(ctx, query)
 
-	// Send typing action to show the bot is processing
-	typingAction := tgbotapi.NewChatAction(message.Chat.ID, tgbotapi.ChatTyping)
-	_, _ = b.api.Send(typingAction)
+	token, err := getRedditAccessToken()
+	if err != nil {
+		loggerFromContext(ctx).Error("Failed to get access token", "error", err)
+		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("❌ Error: Failed to connect to Reddit. Please try again later.\n\nTechnical details: %v", err))
+		_, _ = b.api.Send(errorMsg)
+		return err
+	}
 
-	// Send initial processing message
-	processingMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("üîç Analyzing r/%s...\nThis might take a moment to fetch and process the data.", strings.TrimPrefix(subredditName, "r/")))
-	sentMsg, _ := b.api.Send(processingMsg)
+	resolved, candidates, err := b.resolveSubreddit(ctx, query, token)
+	if err != nil {
+		return b.sendSubredditError(message.Chat.ID, query, err)
+	}
+	if resolved == "" {
+		return b.sendSubredditPicker(message.Chat.ID, query, agent, candidates)
+	}
+
+	return b.analyzeSubreddit(ctx, message.Chat.ID, message.From, resolved, agent, token)
+}
+
+// parseAgentPrefix splits text on its first whitespace run and, if the
+// first word names one of b.agents, returns that agent plus the remaining
+// text as the subreddit query. Otherwise it returns the user's current
+// agent and text unchanged.
+func (b *Bot) parseAgentPrefix(text string) (Agent, string) {
+	b.agentMutex.RLock()
+	currentAgent, _ := findAgent(b.agents, b.currentAgent)
+	b.agentMutex.RUnlock()
+
+	fields := strings.SplitN(text, " ", 2)
+	if len(fields) == 2 {
+		if agent, ok := findAgent(b.agents, fields[0]); ok {
+			return agent, strings.TrimSpace(fields[1])
+		}
+	}
+	return currentAgent, text
+}
+
+// resolveSubreddit looks up query against Reddit's autocomplete endpoint. It
+// returns the resolved subreddit name directly when query is an exact match
+// or autocomplete found exactly one candidate; otherwise it returns an empty
+// name and the candidate list so the caller can show a picker. When
+// autocomplete returns nothing at all, query is passed through unchanged so
+// the normal fetch still runs and can surface its own not-found error.
+func (b *Bot) resolveSubreddit(ctx context.Context, query, token string) (resolved string, candidates []SubredditCandidate, err error) {
+	candidates, err = resolveSubredditCandidates(ctx, query, token)
+	if err != nil {
+		loggerFromContext(ctx).Warn("Subreddit autocomplete failed, falling back to direct lookup", "query", query, "error", err)
+		return query, nil, nil
+	}
+
+	if len(candidates) == 0 {
+		return query, nil, nil
+	}
+	for _, candidate := range candidates {
+		if strings.EqualFold(candidate.Name, query) {
+			return candidate.Name, nil, nil
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0].Name, nil, nil
+	}
+
+	return "", candidates, nil
+}
+
+// sendSubredditPicker sends an inline keyboard letting the user choose among
+// query's ambiguous autocomplete candidates. Tapping a button delivers a
+// callback query handled by handleCallbackQuery, which carries agent's
+// codename along so the picked subreddit is still analyzed by the agent the
+// user originally asked for.
+func (b *Bot) sendSubredditPicker(chatID int64, query string, agent Agent, candidates []SubredditCandidate) error {
+	const maxCandidates = 5
+
+	shown := candidates
+	if len(shown) > maxCandidates {
+		shown = shown[:maxCandidates]
+	}
+
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(shown))
+	for _, candidate := range shown {
+		label := fmt.Sprintf("r/%s", candidate.Name)
+		if candidate.Subscribers > 0 {
+			label = fmt.Sprintf("%s (%d subscribers)", label, candidate.Subscribers)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("subreddit:%s:%s", agent.Codename, candidate.Name)),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🔍 Did you mean one of these for \"%s\"?", query))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	_, err := b.api.Send(msg)
+	return err
+}
+
+// handleCallbackQuery handles a tap on a sendSubredditPicker button,
+// acknowledging the callback and running the analysis for the chosen
+// subreddit and agent.
+func (b *Bot) handleCallbackQuery(ctx context.Context, cb *tgbotapi.CallbackQuery) error {
+	if cb.From.ID != b.config.AuthorizedUserID {
+		_, err := b.api.Request(tgbotapi.NewCallback(cb.ID, "Not authorized"))
+		return err
+	}
+
+	payload, ok := strings.CutPrefix(cb.Data, "subreddit:")
+	if !ok {
+		_, err := b.api.Request(tgbotapi.NewCallback(cb.ID, ""))
+		return err
+	}
+	agentCodename, subredditName, ok := strings.Cut(payload, ":")
+	if !ok {
+		_, err := b.api.Request(tgbotapi.NewCallback(cb.ID, ""))
+		return err
+	}
+	agent, ok := findAgent(b.agents, agentCodename)
+	if !ok {
+		_, err := b.api.Request(tgbotapi.NewCallback(cb.ID, "Unknown agent"))
+		return err
+	}
+	ctx = withSubreddit(ctx, subredditName)
+
+	if _, err := b.api.Request(tgbotapi.NewCallback(cb.ID, fmt.Sprintf("Analyzing r/%s...", subredditName))); err != nil {
+		loggerFromContext(ctx).Error("Failed to acknowledge callback query", "error", err)
+	}
 
-	// Get Reddit data
 	token, err := getRedditAccessToken()
 	if err != nil {
-		b.logger.Printf("Failed to get access token: %v", err)
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("‚ùå Error: Failed to connect to Reddit. Please try again later.\n\nTechnical details: %v", err))
+		loggerFromContext(ctx).Error("Failed to get access token", "error", err)
+		errorMsg := tgbotapi.NewMessage(cb.Message.Chat.ID, fmt.Sprintf("❌ Error: Failed to connect to Reddit. Please try again later.\n\nTechnical details: %v", err))
 		_, _ = b.api.Send(errorMsg)
 		return err
 	}
 
+	return b.analyzeSubreddit(ctx, cb.Message.Chat.ID, cb.From, subredditName, agent, token)
+}
+
+// sendSubredditError surfaces a friendly, specific message for the
+// distinct failure states Reddit reports for a subreddit name (private,
+// quarantined, banned, or simply nonexistent), falling back to the
+// underlying error for anything else.
+func (b *Bot) sendSubredditError(chatID int64, subredditName string, err error) error {
+	var text string
+	switch {
+	case errors.Is(err, ErrSubredditPrivate):
+		text = fmt.Sprintf("🔒 r/%s is a private community. You'll need to be an approved member to see it.", subredditName)
+	case errors.Is(err, ErrSubredditQuarantined):
+		text = fmt.Sprintf("⚠️ r/%s is quarantined by Reddit and can't be analyzed.", subredditName)
+	case errors.Is(err, ErrSubredditBanned):
+		text = fmt.Sprintf("🚫 r/%s has been banned by Reddit.", subredditName)
+	case errors.Is(err, ErrSubredditNotFound):
+		text = fmt.Sprintf("❓ r/%s doesn't seem to exist. Double-check the spelling and try again.", subredditName)
+	default:
+		text = fmt.Sprintf("❌ Error: %v", err)
+	}
+
+	_, sendErr := b.api.Send(tgbotapi.NewMessage(chatID, text))
+	return sendErr
+}
+
+// analyzeSubreddit runs the fetch+summarize pipeline for subredditName
+// using agent's prompt, model, temperature, and max_tokens, and delivers
+// the result to chatID, recording it (along with agent's codename) in the
+// history store under from's user ID. It's shared by handleMessage (direct
+// or exact-match resolution) and handleCallbackQuery (picker selection).
+func (b *Bot) analyzeSubreddit(ctx context.Context, chatID int64, from *tgbotapi.User, subredditName string, agent Agent, token string) error {
+	ctx = withSubreddit(ctx, subredditName)
+	ctx = withModel(ctx, agent.Model)
+	logger := loggerFromContext(ctx)
+
+	// Send typing action to show the bot is processing
+	typingAction := tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)
+	_, _ = b.api.Send(typingAction)
+
+	// Send initial processing message
+	processingMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("🔍 [%s] Analyzing r/%s...\nThis might take a moment to fetch and process the data.", agent.Name, subredditName))
+	sentMsg, _ := b.api.Send(processingMsg)
+
 	// Update processing message
-	editMsg := tgbotapi.NewEditMessageText(message.Chat.ID, sentMsg.MessageID, fmt.Sprintf("üîç Connected to Reddit! Fetching posts from r/%s...", strings.TrimPrefix(subredditName, "r/")))
+	editMsg := tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, fmt.Sprintf("🔍 Connected to Reddit! Fetching posts from r/%s...", subredditName))
 	_, _ = b.api.Send(editMsg)
 
-	data, err := subredditData(subredditName, token)
+	data, err := subredditData(ctx, subredditName, token)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("‚ùå Error: %v", err))
-		_, _ = b.api.Send(errorMsg)
-		return err
+		return b.sendSubredditError(chatID, subredditName, err)
 	}
 
 	// Update processing message
-	editMsg = tgbotapi.NewEditMessageText(message.Chat.ID, sentMsg.MessageID, "üß† Analyzing Reddit posts and generating summary...")
+	editMsg = tgbotapi.NewEditMessageText(chatID, sentMsg.MessageID, fmt.Sprintf("🧠 Generating a %s summary...", agent.Name))
 	_, _ = b.api.Send(editMsg)
 
-	summary, err := summarizePosts(data)
+	summary, err := summarizeWithAgent(ctx, agent, subredditName, data)
 	if err != nil {
-		errorMsg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("‚ùå Error: Failed to generate summary.\n\nTechnical details: %v", err))
+		errorMsg := tgbotapi.NewMessage(chatID, fmt.Sprintf("❌ Error: Failed to generate summary.\n\nTechnical details: %v", err))
 		_, _ = b.api.Send(errorMsg)
 		return err
 	}
 
 	// Delete the processing message
-	deleteMsg := tgbotapi.NewDeleteMessage(message.Chat.ID, sentMsg.MessageID)
+	deleteMsg := tgbotapi.NewDeleteMessage(chatID, sentMsg.MessageID)
 	_, _ = b.api.Send(deleteMsg)
 
 	// Fetch posts again to get the links
-	posts, err := fetchTopPosts(subredditName, token)
+	posts, err := fetchTopPosts(ctx, subredditName, token)
 	if err != nil {
-		b.logger.Printf("Failed to fetch posts for links: %v", err)
+		logger.Error("Failed to fetch posts for links", "error", err)
 	} else {
 		// Append links to the summary
-		summary += "\n\nüîó Top Posts\n"
+		summary += "\n\n🔗 Top Posts\n"
 		// Define emoji numbers for better visual appeal
-		emojiNumbers := []string{"1Ô∏è‚É£", "2Ô∏è‚É£", "3Ô∏è‚É£", "4Ô∏è‚É£", "5Ô∏è‚É£", "6Ô∏è‚É£", "7Ô∏è‚É£", "8Ô∏è‚É£", "9Ô∏è‚É£", "üîü"}
+		emojiNumbers := []string{"1️⃣", "2️⃣", "3️⃣", "4️⃣", "5️⃣", "6️⃣", "7️⃣", "8️⃣", "9️⃣", "🔟"}
 		for i, post := range posts {
 			if i >= defaultPostLimit {
 				break
@@ -314,16 +488,34 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) error {
 		}
 	}
 
+	// Record the completed analysis so it can be recalled via /recent,
+	// /search, and /resummarize. Enqueued rather than written directly, so
+	// a slow store never delays the reply below; failures are logged by
+	// the writer goroutine itself.
+	b.historyWriter.Enqueue(ChatHistoryEntry{
+		UserID:    from.ID,
+		Username:  from.UserName,
+		Subreddit: subredditName,
+		Summary:   summary,
+		Model:     agent.Model,
+		Agent:     agent.Codename,
+		Timestamp: time.Now(),
+	})
+
 	// Send the summary
-	reply := tgbotapi.NewMessage(message.Chat.ID, summary)
+	reply := tgbotapi.NewMessage(chatID, summary)
 	reply.ParseMode = "Markdown"
 	_, err = b.api.Send(reply)
 
 	return err
 }
 
-// handleCommand processes bot commands
-func (b *Bot) handleCommand(message *tgbotapi.Message) error {
+// handleCommand processes bot commands. ctx carries the request ID stamped
+// by Start, so the "Handling command" line below can be correlated with
+// whatever the command goes on to log (e.g. /watch's watcher creation).
+func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) error {
+	loggerFromContext(ctx).Info("Handling command", "command", message.Command())
+
 	switch message.Command() {
 	case "start":
 		welcomeText := `üëã *Welcome to SubTrends Bot!*
@@ -353,7 +545,13 @@ Let's get started!`
 /help - Show this help message
 /history - Show your saved subreddit history
 /clearhistory - Clear your saved subreddit history
-/model - Show or change the current AI model
+/recent N - Show your last N analyses, with their summaries
+/search <query> - Search your past analyses by subreddit or summary text
+/resummarize <subreddit> - Re-send your last summary for a subreddit
+/agent - Show or change the current summarization agent
+/watch <subreddit> daily HH:MM|every <duration> [minscore N] - Schedule recurring digests
+/watchers - List your active watchers
+/unwatch <id> - Stop a watcher
 
 *How to use:*
 Just send any subreddit name (with or without "r/") to get a summary of what's trending there.
@@ -363,6 +561,14 @@ Just send any subreddit name (with or without "r/") to get a summary of what's t
 - datascience
 - askhistorians
 
+To use a specific agent for just one request, prefix it with the agent's codename:
+- news-brief r/worldnews
+- eli5 askscience
+
+To get a recurring digest instead of asking each time:
+- /watch worldnews daily 09:00
+- /watch technology every 6h minscore 500
+
 The bot will analyze the top posts and comments from the past day and provide you with a concise, organized summary.`
 
 		msg := tgbotapi.NewMessage(message.Chat.ID, helpText)
@@ -376,8 +582,26 @@ The bot will analyze the top posts and comments from the past day and provide yo
 	case "clearhistory":
 		return b.handleClearHistoryCommand(message)
 
-	case "model":
-		return b.handleModelCommand(message)
+	case "recent":
+		return b.handleRecentCommand(message)
+
+	case "search":
+		return b.handleSearchCommand(message)
+
+	case "resummarize":
+		return b.handleResummarizeCommand(message)
+
+	case "agent":
+		return b.handleAgentCommand(message)
+
+	case "watch":
+		return b.handleWatchCommand(message)
+
+	case "watchers":
+		return b.handleWatchersCommand(message)
+
+	case "unwatch":
+		return b.handleUnwatchCommand(message)
 
 	default:
 		msg := tgbotapi.NewMessage(message.Chat.ID, "Unknown command. Try /help to see available commands.")
@@ -386,107 +610,191 @@ The bot will analyze the top posts and comments from the past day and provide yo
 	}
 }
 
-// handleHistoryCommand handles the /history command
+// handleHistoryCommand handles the /history command, listing the distinct
+// subreddits the user has analyzed, most recent first.
 func (b *Bot) handleHistoryCommand(message *tgbotapi.Message) error {
-	b.historyMutex.RLock()
-	defer b.historyMutex.RUnlock()
+	subreddits, err := b.historyStore.Subreddits(message.From.ID)
+	if err != nil {
+		b.logger.Error("Error reading history", "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Error reading your history.")
+		_, sendErr := b.api.Send(msg)
+		return sendErr
+	}
 
-	if len(b.history) == 0 {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "üìú *Subreddit History*\n\nYou haven't visited any subreddits yet.")
+	if len(subreddits) == 0 {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "📜 *Subreddit History*\n\nYou haven't visited any subreddits yet.")
 		msg.ParseMode = "Markdown"
 		_, err := b.api.Send(msg)
 		return err
 	}
 
-	// Build the history message
 	var historyText strings.Builder
-	historyText.WriteString("üìú *Your Subreddit History*\n\n")
-
-	// Display the subreddits in reverse order (assuming newest is at the end)
-	for i := len(b.history) - 1; i >= 0; i-- {
-		subreddit := b.history[i]
-		historyText.WriteString(fmt.Sprintf("‚Ä¢ `%s`\n", subreddit))
+	historyText.WriteString("📜 *Your Subreddit History*\n\n")
+	for _, subreddit := range subreddits {
+		historyText.WriteString(fmt.Sprintf("• `%s`\n", subreddit))
 	}
 
 	msg := tgbotapi.NewMessage(message.Chat.ID, historyText.String())
 	msg.ParseMode = "Markdown"
-	_, err := b.api.Send(msg)
+	_, err = b.api.Send(msg)
 	return err
 }
 
 // handleClearHistoryCommand handles the /clearhistory command
 func (b *Bot) handleClearHistoryCommand(message *tgbotapi.Message) error {
-	b.historyMutex.Lock()
+	if err := b.historyStore.Clear(message.From.ID); err != nil {
+		b.logger.Error("Error clearing history", "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Error clearing history.")
+		_, err := b.api.Send(msg)
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, "✅ Subreddit history has been cleared.")
+	_, err := b.api.Send(msg)
+	return err
+}
 
-	// Clear the history
-	b.history = make([]string, 0, 50)
+// handleRecentCommand handles "/recent N", showing the user's last N
+// analyses (default 5) along with their stored summaries.
+func (b *Bot) handleRecentCommand(message *tgbotapi.Message) error {
+	n := 5
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		parsed, err := strconv.Atoi(args)
+		if err != nil || parsed <= 0 {
+			msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Usage: `/recent N`, where N is a positive number.")
+			msg.ParseMode = "Markdown"
+			_, err := b.api.Send(msg)
+			return err
+		}
+		n = parsed
+	}
+
+	entries, err := b.historyStore.Recent(message.From.ID, n)
+	if err != nil {
+		b.logger.Error("Error reading recent history", "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Error reading your history.")
+		_, sendErr := b.api.Send(msg)
+		return sendErr
+	}
+
+	return b.sendHistoryEntries(message.Chat.ID, entries, fmt.Sprintf("📜 *Your Last %d Analyses*", n))
+}
+
+// handleSearchCommand handles "/search <query>", matching against the
+// user's stored subreddit names and summary text.
+func (b *Bot) handleSearchCommand(message *tgbotapi.Message) error {
+	query := strings.TrimSpace(message.CommandArguments())
+	if query == "" {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Usage: `/search <query>`")
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
+	}
+
+	entries, err := b.historyStore.Search(message.From.ID, query)
+	if err != nil {
+		b.logger.Error("Error searching history", "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Error searching your history.")
+		_, sendErr := b.api.Send(msg)
+		return sendErr
+	}
 
-	// Save the empty history to file
-	err := b.saveHistoryToFile()
+	return b.sendHistoryEntries(message.Chat.ID, entries, fmt.Sprintf("🔍 *Results for \"%s\"*", query))
+}
 
-	b.historyMutex.Unlock()
+// handleResummarizeCommand handles "/resummarize <subreddit>", re-sending
+// the user's most recently stored summary for that subreddit rather than
+// generating a new one.
+func (b *Bot) handleResummarizeCommand(message *tgbotapi.Message) error {
+	subreddit := strings.TrimPrefix(strings.TrimSpace(message.CommandArguments()), "r/")
+	if subreddit == "" {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Usage: `/resummarize <subreddit>`")
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
+	}
 
+	entry, err := b.historyStore.Latest(message.From.ID, subreddit)
 	if err != nil {
-		b.logger.Printf("Error saving empty history to file: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, "‚ùå Error clearing history.")
+		b.logger.Error("Error looking up history", "subreddit", subreddit, "error", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Error reading your history.")
+		_, sendErr := b.api.Send(msg)
+		return sendErr
+	}
+	if entry == nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("📜 No saved summary found for r/%s.", subreddit))
 		_, err := b.api.Send(msg)
 		return err
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, "‚úÖ Subreddit history has been cleared.")
-	_, err = b.api.Send(msg)
+	reply := tgbotapi.NewMessage(message.Chat.ID, entry.Summary)
+	reply.ParseMode = "Markdown"
+	_, err = b.api.Send(reply)
 	return err
 }
 
-// handleModelCommand handles the /model command
-func (b *Bot) handleModelCommand(message *tgbotapi.Message) error {
-	args := message.CommandArguments()
-	if args == "" {
-		// Show current model
-		b.modelMutex.RLock()
-		currentModel := b.model
-		b.modelMutex.RUnlock()
-
-		var modelText strings.Builder
-		modelText.WriteString("*Current AI Model*\n\n")
-
-		// Find current model info
-		var currentModelInfo ModelInfo
-		for _, model := range availableModels {
-			if model.Name == currentModel {
-				currentModelInfo = model
-				break
-			}
-		}
-		modelText.WriteString(fmt.Sprintf("Currently using: `%s` (%s)\n", currentModelInfo.Codename, currentModelInfo.Description))
-		modelText.WriteString("\n*Available Models:*\n")
-		for _, model := range availableModels {
-			modelText.WriteString(fmt.Sprintf("- `%s`: %s\n", model.Codename, model.Description))
-		}
-		modelText.WriteString("\nTo change the model, use:\n`/model <codename>`")
-
-		msg := tgbotapi.NewMessage(message.Chat.ID, modelText.String())
+// sendHistoryEntries renders entries as a bulleted digest under header,
+// used by /recent and /search. Each entry's summary is truncated to keep
+// the digest readable; the full text is always available via
+// /resummarize.
+func (b *Bot) sendHistoryEntries(chatID int64, entries []ChatHistoryEntry, header string) error {
+	if len(entries) == 0 {
+		msg := tgbotapi.NewMessage(chatID, header+"\n\nNothing found.")
 		msg.ParseMode = "Markdown"
 		_, err := b.api.Send(msg)
 		return err
 	}
 
-	// Validate model codename
-	var selectedModel ModelInfo
-	validModel := false
-	for _, model := range availableModels {
-		if args == model.Codename {
-			validModel = true
-			selectedModel = model
-			break
+	const excerptLen = 200
+	var builder strings.Builder
+	builder.WriteString(header + "\n\n")
+	for _, entry := range entries {
+		excerpt := entry.Summary
+		if len(excerpt) > excerptLen {
+			excerpt = excerpt[:excerptLen] + "…"
+		}
+		builder.WriteString(fmt.Sprintf("• `r/%s` (%s, %s)\n%s\n\n", entry.Subreddit, entry.Agent, entry.Timestamp.Format("2006-01-02 15:04"), excerpt))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, builder.String())
+	_, err := b.api.Send(msg)
+	return err
+}
+
+// handleAgentCommand handles the /agent command: with no arguments it shows
+// the current agent and the full catalog; with a codename argument it
+// switches the user's default agent for future messages (a per-message
+// agent prefix like "news-brief r/worldnews" overrides this without
+// changing it).
+func (b *Bot) handleAgentCommand(message *tgbotapi.Message) error {
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		b.agentMutex.RLock()
+		currentAgent, _ := findAgent(b.agents, b.currentAgent)
+		b.agentMutex.RUnlock()
+
+		var agentText strings.Builder
+		agentText.WriteString("*Current Agent*\n\n")
+		agentText.WriteString(fmt.Sprintf("Currently using: `%s` (%s)\n", currentAgent.Codename, currentAgent.Name))
+		agentText.WriteString("\n*Available Agents:*\n")
+		for _, agent := range b.agents {
+			agentText.WriteString(fmt.Sprintf("- `%s`: %s\n", agent.Codename, agent.Name))
 		}
+		agentText.WriteString("\nTo change the default agent, use:\n`/agent <codename>`")
+		agentText.WriteString("\nOr route a single message to an agent:\n`<codename> r/<subreddit>`")
+
+		msg := tgbotapi.NewMessage(message.Chat.ID, agentText.String())
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
 	}
 
-	if !validModel {
+	selectedAgent, ok := findAgent(b.agents, args)
+	if !ok {
 		var codenames strings.Builder
-		codenames.WriteString("‚ùå Invalid model codename. Available models:\n")
-		for _, model := range availableModels {
-			codenames.WriteString(fmt.Sprintf("- `%s`: %s\n", model.Codename, model.Description))
+		codenames.WriteString("❌ Invalid agent codename. Available agents:\n")
+		for _, agent := range b.agents {
+			codenames.WriteString(fmt.Sprintf("- `%s`: %s\n", agent.Codename, agent.Name))
 		}
 		msg := tgbotapi.NewMessage(message.Chat.ID, codenames.String())
 		msg.ParseMode = "Markdown"
@@ -494,48 +802,167 @@ func (b *Bot) handleModelCommand(message *tgbotapi.Message) error {
 		return err
 	}
 
-	// Update model
-	b.modelMutex.Lock()
-	b.model = selectedModel.Name
-	b.modelMutex.Unlock()
+	b.agentMutex.Lock()
+	b.currentAgent = selectedAgent.Codename
+	b.agentMutex.Unlock()
 
-	// Update environment variable
-	os.Setenv("ANTHROPIC_MODEL", selectedModel.Name)
-
-	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("‚úÖ Model changed to: `%s` (%s)", selectedModel.Codename, selectedModel.Description))
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("✅ Agent changed to: `%s` (%s)", selectedAgent.Codename, selectedAgent.Name))
 	msg.ParseMode = "Markdown"
 	_, err := b.api.Send(msg)
 	return err
 }
 
-// saveToHistory saves a subreddit name to the history if it's not a command
-func (b *Bot) saveToHistory(message *tgbotapi.Message) {
-	// Skip commands
-	if message.IsCommand() {
-		return
+// handleWatchCommand handles "/watch <subreddit> daily HH:MM" or
+// "/watch <subreddit> every <duration>", optionally followed by
+// "minscore N" to only deliver when the subreddit's current top post clears
+// that score. The watcher is created for the user's current default agent.
+func (b *Bot) handleWatchCommand(message *tgbotapi.Message) error {
+	usage := "❌ Usage: `/watch <subreddit> daily HH:MM` or `/watch <subreddit> every <duration>`, optionally followed by `minscore N`"
+
+	fields := strings.Fields(message.CommandArguments())
+	if len(fields) < 3 {
+		msg := tgbotapi.NewMessage(message.Chat.ID, usage)
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
 	}
 
-	// Clean the subreddit name (remove r/ prefix if present)
-	subredditName := strings.TrimPrefix(message.Text, "r/")
+	subreddit := strings.TrimPrefix(fields[0], "r/")
 
-	b.historyMutex.Lock()
-	defer b.historyMutex.Unlock()
+	var schedule TelegramWatcherSchedule
+	switch strings.ToLower(fields[1]) {
+	case "daily":
+		if _, err := time.Parse("15:04", fields[2]); err != nil {
+			msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid time %q, expected HH:MM", fields[2]))
+			_, sendErr := b.api.Send(msg)
+			if sendErr != nil {
+				return sendErr
+			}
+			return err
+		}
+		schedule = TelegramWatcherSchedule{Daily: true, DailyAt: fields[2]}
+	case "every":
+		interval, err := time.ParseDuration(fields[2])
+		if err != nil {
+			msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid duration %q: %v", fields[2], err))
+			_, sendErr := b.api.Send(msg)
+			if sendErr != nil {
+				return sendErr
+			}
+			return err
+		}
+		schedule = TelegramWatcherSchedule{Interval: interval}
+	default:
+		msg := tgbotapi.NewMessage(message.Chat.ID, usage)
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
+	}
 
-	// Check if this subreddit is already in history
-	for _, existingSubreddit := range b.history {
-		if strings.EqualFold(existingSubreddit, subredditName) {
-			// Subreddit already in history, nothing to do
-			return
+	minScore := 0
+	if rest := fields[3:]; len(rest) >= 2 && strings.EqualFold(rest[0], "minscore") {
+		n, err := strconv.Atoi(rest[1])
+		if err != nil {
+			msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("❌ Invalid minscore %q: %v", rest[1], err))
+			_, sendErr := b.api.Send(msg)
+			if sendErr != nil {
+				return sendErr
+			}
+			return err
 		}
+		minScore = n
 	}
 
-	// Add new unique subreddit to history
-	b.history = append(b.history, subredditName)
+	userID := message.From.ID
+	if b.watcherStore.CountByUser(userID) >= AppConfig.MaxWatchersPerUser {
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("❌ You already have the maximum of %d watchers", AppConfig.MaxWatchersPerUser))
+		_, err := b.api.Send(msg)
+		return err
+	}
 
-	// Save history to file after adding a new item
-	go func() {
-		if err := b.saveHistoryToFile(); err != nil {
-			b.logger.Printf("Error saving history to file after adding new subreddit: %v", err)
+	b.agentMutex.RLock()
+	agentCodename := b.currentAgent
+	b.agentMutex.RUnlock()
+
+	w := &TelegramWatcher{
+		ID:          fmt.Sprintf("%d-%s-%d", userID, subreddit, time.Now().UnixNano()),
+		UserID:      userID,
+		ChatID:      message.Chat.ID,
+		Subreddit:   subreddit,
+		Agent:       agentCodename,
+		Schedule:    schedule,
+		MinTopScore: minScore,
+	}
+	if err := b.watcherStore.Add(w); err != nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Failed to save watcher")
+		_, sendErr := b.api.Send(msg)
+		if sendErr != nil {
+			return sendErr
 		}
-	}()
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("👀 Watching r/%s, %s. ID: `%s`", subreddit, schedule, w.ID))
+	msg.ParseMode = "Markdown"
+	_, err := b.api.Send(msg)
+	return err
 }
+
+// handleWatchersCommand handles "/watchers", listing the user's active
+// watchers.
+func (b *Bot) handleWatchersCommand(message *tgbotapi.Message) error {
+	watchers := b.watcherStore.ListByUser(message.From.ID)
+
+	content := "📝 You have no active watchers. Use `/watch <subreddit> daily HH:MM` to start one!"
+	if len(watchers) > 0 {
+		var builder strings.Builder
+		builder.WriteString("📝 *Your Watchers*\n\n")
+		for _, w := range watchers {
+			scoreSuffix := ""
+			if w.MinTopScore > 0 {
+				scoreSuffix = fmt.Sprintf(" (min score %d)", w.MinTopScore)
+			}
+			builder.WriteString(fmt.Sprintf("• `%s` — r/%s, %s%s\n", w.ID, w.Subreddit, w.Schedule, scoreSuffix))
+		}
+		content = builder.String()
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, content)
+	msg.ParseMode = "Markdown"
+	_, err := b.api.Send(msg)
+	return err
+}
+
+// handleUnwatchCommand handles "/unwatch <id>", removing a watcher owned by
+// the requesting user.
+func (b *Bot) handleUnwatchCommand(message *tgbotapi.Message) error {
+	id := strings.TrimSpace(message.CommandArguments())
+	if id == "" {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Usage: `/unwatch <id>`")
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
+	}
+
+	removed, err := b.watcherStore.Remove(id, message.From.ID)
+	if err != nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ Failed to remove watcher")
+		_, sendErr := b.api.Send(msg)
+		if sendErr != nil {
+			return sendErr
+		}
+		return err
+	}
+	if !removed {
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("❓ No watcher `%s` found for you", id))
+		msg.ParseMode = "Markdown"
+		_, err := b.api.Send(msg)
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("✅ Stopped watching `%s`", id))
+	msg.ParseMode = "Markdown"
+	_, err = b.api.Send(msg)
+	return err
+}
+
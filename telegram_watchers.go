@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TelegramWatcherSchedule is when a TelegramWatcher should fire: either once
+// a day at a fixed time, or on a fixed interval. Exactly one of the two
+// modes is meaningful for a given schedule, selected by Daily.
+type TelegramWatcherSchedule struct {
+	Daily    bool          `json:"daily,omitempty"`
+	DailyAt  string        `json:"daily_at,omitempty"` // "HH:MM", only meaningful when Daily
+	Interval time.Duration `json:"interval,omitempty"` // only meaningful when !Daily
+}
+
+// due reports whether the schedule should fire now, given the last time it
+// fired. A daily schedule fires once in the minute matching DailyAt, guarded
+// against firing twice in the same day if checked more than once during that
+// minute or after a restart. An interval schedule fires once Interval has
+// elapsed since lastFired, so a restart doesn't cause an early re-fire.
+func (s TelegramWatcherSchedule) due(now, lastFired time.Time) bool {
+	if s.Daily {
+		if now.Format("15:04") != s.DailyAt {
+			return false
+		}
+		return lastFired.IsZero() || !sameDay(lastFired, now)
+	}
+
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return now.Sub(lastFired) >= interval
+}
+
+// String renders the schedule for /watchers, e.g. "daily at 09:00" or
+// "every 6h0m0s".
+func (s TelegramWatcherSchedule) String() string {
+	if s.Daily {
+		return fmt.Sprintf("daily at %s", s.DailyAt)
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return fmt.Sprintf("every %s", interval)
+}
+
+// sameDay reports whether a and b fall on the same calendar day.
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// TelegramWatcher is a standing request to re-summarize a subreddit on a
+// schedule and deliver the result to a Telegram chat, optionally only when
+// the top post's score clears MinTopScore.
+type TelegramWatcher struct {
+	ID          string                  `json:"id"`
+	UserID      int64                   `json:"user_id"`
+	ChatID      int64                   `json:"chat_id"`
+	Subreddit   string                  `json:"subreddit"`
+	Agent       string                  `json:"agent"`
+	Schedule    TelegramWatcherSchedule `json:"schedule"`
+	MinTopScore int                     `json:"min_top_score,omitempty"`
+	LastFired   time.Time               `json:"last_fired"`
+}
+
+// TelegramWatcherStore is a JSON-file-backed store of TelegramWatchers,
+// rewritten in full via WriteJSONFile on every change.
+type TelegramWatcherStore struct {
+	filePath string
+	mutex    sync.RWMutex
+	byID     map[string]*TelegramWatcher
+}
+
+// NewTelegramWatcherStore creates a TelegramWatcherStore, loading any
+// existing data from filePath.
+func NewTelegramWatcherStore(filePath string) (*TelegramWatcherStore, error) {
+	store := &TelegramWatcherStore{
+		filePath: filePath,
+		byID:     make(map[string]*TelegramWatcher),
+	}
+
+	if err := ReadJSONFile(filePath, &store.byID); err != nil {
+		return nil, fmt.Errorf("failed to read telegram watcher store: %w", err)
+	}
+	if store.byID == nil {
+		store.byID = make(map[string]*TelegramWatcher)
+	}
+
+	return store, nil
+}
+
+// CountByUser returns how many watchers userID currently has registered.
+func (s *TelegramWatcherStore) CountByUser(userID int64) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	count := 0
+	for _, w := range s.byID {
+		if w.UserID == userID {
+			count++
+		}
+	}
+	return count
+}
+
+// Add registers a new watcher and persists the store.
+func (s *TelegramWatcherStore) Add(w *TelegramWatcher) error {
+	s.mutex.Lock()
+	s.byID[w.ID] = w
+	s.mutex.Unlock()
+	return s.persist()
+}
+
+// Remove deletes the watcher with id if it's owned by userID, returning
+// false if it doesn't exist or belongs to someone else.
+func (s *TelegramWatcherStore) Remove(id string, userID int64) (bool, error) {
+	s.mutex.Lock()
+	w, ok := s.byID[id]
+	if !ok || w.UserID != userID {
+		s.mutex.Unlock()
+		return false, nil
+	}
+	delete(s.byID, id)
+	s.mutex.Unlock()
+
+	return true, s.persist()
+}
+
+// ListByUser returns every watcher owned by userID.
+func (s *TelegramWatcherStore) ListByUser(userID int64) []*TelegramWatcher {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var result []*TelegramWatcher
+	for _, w := range s.byID {
+		if w.UserID == userID {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// All returns every registered watcher.
+func (s *TelegramWatcherStore) All() []*TelegramWatcher {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make([]*TelegramWatcher, 0, len(s.byID))
+	for _, w := range s.byID {
+		result = append(result, w)
+	}
+	return result
+}
+
+// persist writes the full watcher map to disk.
+func (s *TelegramWatcherStore) persist() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return WriteJSONFile(s.filePath, s.byID)
+}
+
+// TelegramWatcherScheduler periodically fires every registered
+// TelegramWatcher whose schedule is due, delivering a fresh agent summary to
+// its chat.
+type TelegramWatcherScheduler struct {
+	bot   *Bot
+	store *TelegramWatcherStore
+}
+
+// NewTelegramWatcherScheduler creates a scheduler backed by store.
+func NewTelegramWatcherScheduler(bot *Bot, store *TelegramWatcherStore) *TelegramWatcherScheduler {
+	return &TelegramWatcherScheduler{bot: bot, store: store}
+}
+
+// Run ticks the scheduler every minute until stopChan is closed, firing any
+// watcher whose Schedule is due.
+func (ws *TelegramWatcherScheduler) Run(stopChan <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopChan:
+			log.Println("Telegram watcher scheduler stopped")
+			return
+		case <-ticker.C:
+			ws.tick()
+		}
+	}
+}
+
+// tick fires every due watcher. Reddit calls made during a fire go through
+// the shared rate-limited RedditClient, so firing many watchers in the same
+// tick is still paced by its requestsPerSecond limiter.
+func (ws *TelegramWatcherScheduler) tick() {
+	now := time.Now()
+	for _, w := range ws.store.All() {
+		if !w.Schedule.due(now, w.LastFired) {
+			continue
+		}
+		ws.fire(w)
+	}
+}
+
+// fire re-summarizes w.Subreddit and delivers it to w.ChatID, skipping
+// delivery (but still marking the watcher as fired, so a slow-to-clear
+// threshold doesn't retrigger every minute) when w.MinTopScore is set and
+// the subreddit's current top post doesn't clear it.
+func (ws *TelegramWatcherScheduler) fire(w *TelegramWatcher) {
+	agent, ok := findAgent(ws.bot.agents, w.Agent)
+	if !ok {
+		ws.bot.agentMutex.RLock()
+		agent, _ = findAgent(ws.bot.agents, ws.bot.currentAgent)
+		ws.bot.agentMutex.RUnlock()
+	}
+
+	w.LastFired = time.Now()
+	if err := ws.store.Add(w); err != nil {
+		log.Printf("ERROR: Telegram watcher %s: failed to persist: %v", w.ID, err)
+	}
+
+	token, err := getRedditAccessToken()
+	if err != nil {
+		log.Printf("ERROR: Telegram watcher %s: failed to authenticate with Reddit: %v", w.ID, err)
+		return
+	}
+
+	if w.MinTopScore > 0 {
+		posts, err := fetchTopPosts(context.Background(), w.Subreddit, token)
+		if err != nil {
+			log.Printf("ERROR: Telegram watcher %s: failed to fetch r/%s: %v", w.ID, w.Subreddit, err)
+			return
+		}
+		if len(posts) == 0 || posts[0].Ups < w.MinTopScore {
+			return
+		}
+	}
+
+	data, err := subredditData(context.Background(), w.Subreddit, token)
+	if err != nil {
+		log.Printf("ERROR: Telegram watcher %s: failed to fetch r/%s: %v", w.ID, w.Subreddit, err)
+		return
+	}
+
+	summary, err := summarizeWithAgent(context.Background(), agent, w.Subreddit, data)
+	if err != nil {
+		log.Printf("ERROR: Telegram watcher %s: failed to summarize: %v", w.ID, err)
+		return
+	}
+
+	header := fmt.Sprintf("👀 *Watcher alert for r/%s*\n\n", w.Subreddit)
+	msg := tgbotapi.NewMessage(w.ChatID, header+summary)
+	msg.ParseMode = "Markdown"
+	if _, err := ws.bot.api.Send(msg); err != nil {
+		log.Printf("ERROR: Telegram watcher %s: failed to deliver: %v", w.ID, err)
+	}
+}
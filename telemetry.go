@@ -0,0 +1,118 @@
+// Package main: telemetry.go wires up OpenTelemetry tracing and Prometheus
+// metrics for the HTTP handlers and outbound LLM/Reddit calls.
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// tracer emits spans for the Reddit/LLM call paths. It's a no-op tracer
+// (the default from otel.Tracer) until InitializeTelemetry installs a real
+// TracerProvider.
+var tracer = otel.Tracer("subtrends")
+
+var (
+	// subreddit is deliberately not a label on either metric below: it's
+	// arbitrary, attacker-controlled input, and one Prometheus time series
+	// per distinct value would give a single client unbounded control over
+	// the exporter's memory (hit /analyze with enough random subreddit names
+	// and it OOMs). status is a small, fixed set, so it's safe to label by.
+	analyzeRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "analyze_requests_total",
+		Help: "Total /analyze requests, labeled by outcome.",
+	}, []string{"status"})
+
+	analyzeDurationSeconds = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "analyze_duration_seconds",
+		Help:    "Latency of the /analyze fetch+summarize pipeline, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	})
+
+	llmTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "llm_tokens_total",
+		Help: "Tokens consumed by LLM summarization calls, labeled by model and token kind (prompt/completion).",
+	}, []string{"model", "kind"})
+
+	redditRateLimitRemaining = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "reddit_ratelimit_remaining",
+		Help: "Most recently observed x-ratelimit-remaining value from the Reddit API.",
+	})
+)
+
+// InitializeTelemetry sets up the global TracerProvider from cfg. When
+// OTelExporterOTLPEndpoint is unset, tracing stays a no-op and the returned
+// shutdown func is a no-op too. Callers should defer the returned shutdown
+// func on application exit to flush any buffered spans.
+func InitializeTelemetry(cfg *Config) (func(context.Context) error, error) {
+	if cfg.OTelExporterOTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(cfg.OTelExporterOTLPEndpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName("subtrends")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build telemetry resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// instrumentedClient returns an *http.Client whose transport is wrapped with
+// otelhttp, so every outbound request (Reddit, Anthropic, OpenAI) gets a
+// child span when tracing is enabled.
+func instrumentedClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: otelhttp.NewTransport(http.DefaultTransport),
+	}
+}
+
+// registerMetricsRoute exposes Prometheus metrics at /metrics when
+// cfg.MetricsEnabled is set, and attaches the OpenTelemetry Gin middleware
+// so inbound requests get traced too.
+func registerMetricsRoute(router *gin.Engine, cfg *Config) {
+	router.Use(otelgin.Middleware("subtrends"))
+	if !cfg.MetricsEnabled {
+		return
+	}
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+}
+
+// recordLLMTokens records prompt/completion token counts for model in the
+// llm_tokens_total counter.
+func recordLLMTokens(model string, promptTokens, completionTokens int) {
+	if promptTokens > 0 {
+		llmTokensTotal.WithLabelValues(model, "prompt").Add(float64(promptTokens))
+	}
+	if completionTokens > 0 {
+		llmTokensTotal.WithLabelValues(model, "completion").Add(float64(completionTokens))
+	}
+}
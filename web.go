@@ -1,17 +1,27 @@
 package main
 
 import (
+	"container/list"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/sessions"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/time/rate"
 )
 
 // Session represents user session data
@@ -23,15 +33,184 @@ type Session struct {
 	CreatedAt time.Time
 }
 
+// SessionStore persists Session data server-side, keyed by UserID. Sessions
+// used to serialize the full Session (History up to 50 entries included)
+// into the cookie itself, which overflows the 4KB cookie limit quickly;
+// WebServer's cookie now holds only the session ID, and looks the rest up
+// here.
+type SessionStore interface {
+	// Get returns the session for id, or (nil, nil) if there isn't one.
+	Get(ctx context.Context, id string) (*Session, error)
+	Save(ctx context.Context, id string, session *Session) error
+	Delete(ctx context.Context, id string) error
+	// List returns every session currently in the store, e.g. for an admin
+	// view or metrics.
+	List(ctx context.Context) ([]*Session, error)
+}
+
+// newSessionStore builds the SessionStore selected by cfg.SessionBackend:
+// Redis-backed when set to "redis" (requires SessionRedisURL), in-memory
+// otherwise.
+func newSessionStore(cfg *Config) (SessionStore, error) {
+	switch cfg.SessionBackend {
+	case "", "memory":
+		return newMemorySessionStore(), nil
+	case "redis":
+		store, err := newRedisSessionStore(cfg.SessionRedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Redis session store: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown SESSION_BACKEND %q", cfg.SessionBackend)
+	}
+}
+
+// memorySessionStore is an in-process SessionStore. Sessions are lost on
+// restart; fine for a single-instance deployment without Redis configured.
+type memorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+func newMemorySessionStore() *memorySessionStore {
+	return &memorySessionStore{sessions: make(map[string]*Session)}
+}
+
+func (m *memorySessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.sessions[id], nil
+}
+
+func (m *memorySessionStore) Save(ctx context.Context, id string, session *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memorySessionStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *memorySessionStore) List(ctx context.Context) ([]*Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// redisSessionKeyPrefix namespaces session keys in a shared Redis instance.
+const redisSessionKeyPrefix = "subtrends:session:"
+
+// redisSessionStore is a Redis-backed SessionStore, so sessions survive a
+// restart and multiple subtrends replicas can share them.
+type redisSessionStore struct {
+	client *redis.Client
+}
+
+func newRedisSessionStore(redisURL string) (*redisSessionStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	return &redisSessionStore{client: redis.NewClient(opts)}, nil
+}
+
+func (r *redisSessionStore) Get(ctx context.Context, id string) (*Session, error) {
+	raw, err := r.client.Get(ctx, redisSessionKeyPrefix+id).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session from redis: %w", err)
+	}
+
+	var session Session
+	if err := json.Unmarshal([]byte(raw), &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+func (r *redisSessionStore) Save(ctx context.Context, id string, session *Session) error {
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := r.client.Set(ctx, redisSessionKeyPrefix+id, raw, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save session to redis: %w", err)
+	}
+	return nil
+}
+
+func (r *redisSessionStore) Delete(ctx context.Context, id string) error {
+	if err := r.client.Del(ctx, redisSessionKeyPrefix+id).Err(); err != nil {
+		return fmt.Errorf("failed to delete session from redis: %w", err)
+	}
+	return nil
+}
+
+func (r *redisSessionStore) List(ctx context.Context) ([]*Session, error) {
+	keys, err := r.client.Keys(ctx, redisSessionKeyPrefix+"*").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session keys from redis: %w", err)
+	}
+
+	sessions := make([]*Session, 0, len(keys))
+	for _, key := range keys {
+		raw, err := r.client.Get(ctx, key).Result()
+		if err != nil {
+			continue
+		}
+		var session Session
+		if err := json.Unmarshal([]byte(raw), &session); err != nil {
+			continue
+		}
+		sessions = append(sessions, &session)
+	}
+	return sessions, nil
+}
+
 // WebServer represents the web server with its configuration and session store
 type WebServer struct {
 	router       *gin.Engine
 	config       *Config
-	store        *sessions.CookieStore
+	store        *sessions.CookieStore // signs the session-ID cookie
+	sessionStore SessionStore          // persists Session data server-side
 	server       *http.Server
 	stopChan     chan struct{}
 	wg           sync.WaitGroup
 	historyMutex sync.RWMutex
+
+	// inFlight is a buffered-channel semaphore capping concurrent /analyze
+	// requests at config.MaxRequestsInFlight, mirroring the max-in-flight
+	// filter pattern used by Kubernetes' generic apiserver.
+	inFlight chan struct{}
+
+	// userLimiters holds one token-bucket rate.Limiter per client IP, so one
+	// client's bursts can't exhaust another's /analyze budget. It's keyed by
+	// IP rather than session UserID: getSession mints a brand-new session
+	// (and UserID) for any request without a valid cookie, which would
+	// otherwise let the limit be bypassed just by not sending one. Bounded
+	// to config.MaxRateLimiterEntries (LRU-evicted) so it can't grow without
+	// limit either.
+	userLimiters *rateLimiterLRU
+
+	// activeRequests tracks in-flight /analyze and /analyze/stream handlers
+	// so Stop can wait for them to drain (up to its shutdown deadline)
+	// instead of cutting them off mid-summary. draining is flipped by Stop
+	// before that wait begins, so drainMiddleware can reject new requests
+	// with 503 rather than let them start work that's about to be aborted.
+	activeRequests sync.WaitGroup
+	draining       atomic.Bool
 }
 
 // Available models for selection
@@ -68,14 +247,26 @@ func NewWebServer(config *Config) (*WebServer, error) {
 	// Create router
 	router := gin.Default()
 
-	// Create session store
+	// Create the cookie store that signs the session-ID cookie
 	store := sessions.NewCookieStore([]byte(config.SessionSecret))
 
+	// Create the server-side session store that the session-ID cookie keys into
+	sessionStore, err := newSessionStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set up the Reddit/summary cache (Redis-backed if config.RedisURL is set)
+	InitializeCache()
+
 	server := &WebServer{
-		router:   router,
-		config:   config,
-		store:    store,
-		stopChan: make(chan struct{}),
+		router:       router,
+		config:       config,
+		store:        store,
+		sessionStore: sessionStore,
+		stopChan:     make(chan struct{}),
+		inFlight:     make(chan struct{}, config.MaxRequestsInFlight),
+		userLimiters: newRateLimiterLRU(config.MaxRateLimiterEntries),
 	}
 
 	// Setup routes
@@ -98,9 +289,20 @@ func (ws *WebServer) setupRoutes() {
 	// Load HTML templates
 	ws.router.LoadHTMLGlob(filepath.Join(ws.config.TemplatePath, "*.html"))
 
+	// Trace inbound requests and, if enabled, expose Prometheus metrics.
+	registerMetricsRoute(ws.router, ws.config)
+
 	// Routes
 	ws.router.GET("/", ws.handleHome)
-	ws.router.POST("/analyze", ws.handleAnalyze)
+
+	// /analyze and its streaming counterpart fan out to Reddit + the LLM
+	// backend, so they're the routes guarded by the in-flight cap and
+	// per-user rate limit.
+	analyze := ws.router.Group("/analyze")
+	analyze.Use(ws.drainMiddleware(), ws.inFlightMiddleware(), ws.perUserRateLimitMiddleware())
+	analyze.POST("", ws.handleAnalyze)
+	analyze.GET("/stream", ws.handleAnalyzeStream)
+
 	ws.router.GET("/history", ws.handleHistory)
 	ws.router.POST("/clear-history", ws.handleClearHistory)
 	ws.router.GET("/model", ws.handleModelGet)
@@ -108,17 +310,169 @@ func (ws *WebServer) setupRoutes() {
 	ws.router.GET("/health", ws.handleHealth)
 }
 
-// Start begins the web server
+// drainMiddleware registers the request in ws.activeRequests so Stop can
+// wait for it to finish, or, if Stop has already started draining, rejects
+// it with 503 and an X-Shutdown-Draining header instead of letting it begin
+// work that's about to be aborted anyway.
+func (ws *WebServer) drainMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if ws.draining.Load() {
+			c.Header("X-Shutdown-Draining", "true")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Server is shutting down"})
+			return
+		}
+		ws.activeRequests.Add(1)
+		defer ws.activeRequests.Done()
+		c.Next()
+	}
+}
+
+// inFlightMiddleware caps the number of concurrent requests the routes it's
+// attached to can have in flight, returning 429 with Retry-After when
+// saturated. This mirrors the max-in-flight filter pattern Kubernetes'
+// generic apiserver uses: unbounded concurrency here means unbounded
+// concurrent Reddit + LLM calls downstream.
+func (ws *WebServer) inFlightMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		select {
+		case ws.inFlight <- struct{}{}:
+			defer func() { <-ws.inFlight }()
+			c.Next()
+		default:
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Server is busy, please retry shortly"})
+		}
+	}
+}
+
+// perUserRateLimitMiddleware enforces a per-client token-bucket rate limit on
+// the routes it's attached to, keyed by client IP (not the session's
+// UserID, which a cookieless request can mint fresh on every call) so one
+// client's bursts can't exhaust another's budget.
+func (ws *WebServer) perUserRateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ws.userLimiter(c.ClientIP()).Allow() {
+			c.Header("Retry-After", "60")
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded, please slow down"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// userLimiter returns the token-bucket rate.Limiter for clientIP, creating
+// one (configured from AnalyzeRequestsPerMinute/AnalyzeBurstSize) on first
+// use.
+func (ws *WebServer) userLimiter(clientIP string) *rate.Limiter {
+	return ws.userLimiters.Get(clientIP, func() *rate.Limiter {
+		return rate.NewLimiter(rate.Limit(float64(ws.config.AnalyzeRequestsPerMinute)/60), ws.config.AnalyzeBurstSize)
+	})
+}
+
+// rateLimiterEntry is a single bucket tracked by rateLimiterLRU's eviction
+// list.
+type rateLimiterEntry struct {
+	key     string
+	limiter *rate.Limiter
+}
+
+// rateLimiterLRU is a process-local, size-bounded map of rate.Limiter keyed
+// by client identity. It's what bounds perUserRateLimitMiddleware's memory
+// use: without a cap, one bucket per distinct client IP that ever made a
+// request would accumulate forever.
+type rateLimiterLRU struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+// newRateLimiterLRU creates a rateLimiterLRU holding at most capacity
+// buckets.
+func newRateLimiterLRU(capacity int) *rateLimiterLRU {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &rateLimiterLRU{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get returns the limiter for key, creating one via newLimiter and
+// evicting the least-recently-used bucket if that pushes the map over
+// capacity.
+func (l *rateLimiterLRU) Get(key string, newLimiter func() *rate.Limiter) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.entries[key]; ok {
+		l.order.MoveToFront(el)
+		return el.Value.(*rateLimiterEntry).limiter
+	}
+
+	limiter := newLimiter()
+	el := l.order.PushFront(&rateLimiterEntry{key: key, limiter: limiter})
+	l.entries[key] = el
+
+	if l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		if oldest != nil {
+			l.order.Remove(oldest)
+			delete(l.entries, oldest.Value.(*rateLimiterEntry).key)
+		}
+	}
+
+	return limiter
+}
+
+// Start begins the web server. It binds the listener itself (rather than
+// leaving that to ListenAndServe) so the actual bound address is known and
+// logged even when config.Port is "0" — useful for tests and orchestrators
+// that let the OS pick a free port.
 func (ws *WebServer) Start(ctx context.Context) error {
 	ws.wg.Add(1)
 	defer ws.wg.Done()
 
-	log.Printf("Starting web server on port %s", ws.config.Port)
+	listener, err := net.Listen("tcp", ws.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind web server listener: %w", err)
+	}
+
+	var autocertManager *autocert.Manager
+	if len(ws.config.AutocertDomains) > 0 {
+		autocertManager = &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(ws.config.AutocertDomains...),
+			Cache:      autocert.DirCache(ws.config.AutocertCacheDir),
+		}
+		ws.server.TLSConfig = autocertManager.TLSConfig()
+	}
+
+	if err := applyClientAuth(ws.server, ws.config); err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if autocertManager != nil || (ws.config.TLSCertFile != "" && ws.config.TLSKeyFile != "") {
+		scheme = "https"
+	}
+	log.Printf("Starting web server on %s://%s", scheme, listener.Addr().String())
 
 	// Start server in a goroutine
 	go func() {
-		if err := ws.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("Server error: %v", err)
+		var serveErr error
+		switch {
+		case autocertManager != nil:
+			serveErr = ws.server.ServeTLS(listener, "", "")
+		case ws.config.TLSCertFile != "" && ws.config.TLSKeyFile != "":
+			serveErr = ws.server.ServeTLS(listener, ws.config.TLSCertFile, ws.config.TLSKeyFile)
+		default:
+			serveErr = ws.server.Serve(listener)
+		}
+		if serveErr != nil && serveErr != http.ErrServerClosed {
+			log.Printf("Server error: %v", serveErr)
 		}
 	}()
 
@@ -131,10 +485,56 @@ func (ws *WebServer) Start(ctx context.Context) error {
 	}
 }
 
+// applyClientAuth configures mutual TLS on server's TLS config when
+// cfg.ClientCAFile is set, requiring (or, with ClientAuthType set to
+// "VerifyClientCertIfGiven", merely requesting) a client certificate signed
+// by that CA.
+func applyClientAuth(server *http.Server, cfg *Config) error {
+	if cfg.ClientCAFile == "" {
+		return nil
+	}
+
+	caCert, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caCert) {
+		return fmt.Errorf("failed to parse client CA file %s", cfg.ClientCAFile)
+	}
+
+	if server.TLSConfig == nil {
+		server.TLSConfig = &tls.Config{}
+	}
+	server.TLSConfig.ClientCAs = caPool
+	switch cfg.ClientAuthType {
+	case "VerifyClientCertIfGiven":
+		server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	default:
+		server.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return nil
+}
+
 // Stop gracefully stops the web server
 func (ws *WebServer) Stop(ctx context.Context) error {
 	log.Println("Stopping web server...")
 
+	// Reject new /analyze requests immediately, then give in-flight ones up
+	// to ctx's deadline to finish their Reddit + LLM work before the HTTP
+	// server itself shuts down.
+	ws.draining.Store(true)
+	drained := make(chan struct{})
+	go func() {
+		ws.activeRequests.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		log.Printf("WARNING: shutdown deadline reached with analyze requests still in flight")
+	}
+
 	// Signal the server to stop
 	close(ws.stopChan)
 
@@ -159,36 +559,41 @@ func (ws *WebServer) Stop(ctx context.Context) error {
 	}
 }
 
-// getSession retrieves or creates a user session
+// getSession retrieves or creates a user session. The cookie itself only
+// holds the session ID; the actual Session data (including History, which
+// can otherwise overflow the 4KB cookie limit) lives in ws.sessionStore.
 func (ws *WebServer) getSession(c *gin.Context) *Session {
-	session, _ := ws.store.Get(c.Request, "subtrends-session")
-
-	// Get or create session data
-	var sessionData Session
-	if data, ok := session.Values["data"]; ok {
-		if sd, ok := data.(Session); ok {
-			sessionData = sd
+	cookieSession, _ := ws.store.Get(c.Request, "subtrends-session")
+
+	if id, ok := cookieSession.Values["id"].(string); ok && id != "" {
+		sessionData, err := ws.sessionStore.Get(c.Request.Context(), id)
+		if err != nil {
+			log.Printf("Error reading session %s from store: %v", id, err)
+		} else if sessionData != nil {
+			return sessionData
 		}
 	}
 
-	// Initialize if empty
-	if sessionData.UserID == "" {
-		sessionData = Session{
-			UserID:    generateUserID(),
-			History:   make([]string, 0, 50),
-			Model:     ws.config.AnthropicModel,
-			CreatedAt: time.Now(),
-		}
+	// No existing session (missing cookie, or it's expired/gone from the
+	// store): start a fresh one.
+	return &Session{
+		UserID:    generateUserID(),
+		History:   make([]string, 0, 50),
+		Model:     ws.config.AnthropicModel,
+		CreatedAt: time.Now(),
 	}
-
-	return &sessionData
 }
 
-// saveSession saves the session data
+// saveSession persists sessionData to ws.sessionStore and points the
+// session-ID cookie at it.
 func (ws *WebServer) saveSession(c *gin.Context, sessionData *Session) error {
-	session, _ := ws.store.Get(c.Request, "subtrends-session")
-	session.Values["data"] = *sessionData
-	return session.Save(c.Request, c.Writer)
+	cookieSession, _ := ws.store.Get(c.Request, "subtrends-session")
+	cookieSession.Values["id"] = sessionData.UserID
+	if err := cookieSession.Save(c.Request, c.Writer); err != nil {
+		return fmt.Errorf("failed to save session cookie: %w", err)
+	}
+
+	return ws.sessionStore.Save(c.Request.Context(), sessionData.UserID, sessionData)
 }
 
 // generateUserID generates a unique user ID
@@ -220,6 +625,17 @@ func (ws *WebServer) handleAnalyze(c *gin.Context) {
 	// Clean subreddit name
 	subreddit = strings.TrimPrefix(subreddit, "r/")
 
+	ctx, span := tracer.Start(c.Request.Context(), "handleAnalyze")
+	defer span.End()
+	c.Request = c.Request.WithContext(ctx)
+
+	status := "error"
+	startTime := time.Now()
+	defer func() {
+		analyzeRequestsTotal.WithLabelValues(status).Inc()
+		analyzeDurationSeconds.Observe(time.Since(startTime).Seconds())
+	}()
+
 	sessionData := ws.getSession(c)
 
 	// Add to history if not already present
@@ -239,7 +655,101 @@ func (ws *WebServer) handleAnalyze(c *gin.Context) {
 	// Save session
 	ws.saveSession(c, sessionData)
 
-	// Get Reddit data
+	// Serve from appCache unless the caller asked for a fresh result.
+	// "?refresh=1" bypasses the read but still repopulates the cache below,
+	// so the next plain request benefits from it.
+	refresh := c.Query("refresh") == "1"
+	cacheKey := cacheKeyForAnalysis(subreddit, sessionData.Model, ws.config.AnalyzeCacheTTL)
+	ws.setCacheHeaders(c)
+
+	var cached analysisResult
+	if !refresh && getCached(c.Request.Context(), cacheKey, &cached) {
+		status = "cache_hit"
+		c.Header("X-Cache", "HIT")
+		c.JSON(http.StatusOK, gin.H{
+			"summary":   cached.Summary,
+			"posts":     cached.Posts,
+			"subreddit": subreddit,
+		})
+		return
+	}
+
+	// singleflight coalesces concurrent requests for the same cache key into
+	// one upstream fetch+summarize call, so a burst of hits for a trending
+	// subreddit doesn't each pay Reddit + LLM latency/quota separately.
+	// ctx is honored all the way down to Reddit and the LLM backend, so a
+	// request canceled by Stop's shutdown deadline aborts this work instead
+	// of running it to completion in the background.
+	resultAny, err, _ := summaryGroup.Do(cacheKey, func() (interface{}, error) {
+		token, err := getRedditAccessToken()
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to Reddit: %w", err)
+		}
+
+		data, err := subredditData(ctx, subreddit, token)
+		if err != nil {
+			return nil, err
+		}
+
+		summary, err := summarizePosts(ctx, subreddit, data, sessionData.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate summary: %w", err)
+		}
+
+		posts, err := fetchTopPosts(ctx, subreddit, token)
+		if err != nil {
+			log.Printf("Failed to fetch posts for links: %v", err)
+			posts = []RedditPost{} // Ensure posts is never nil
+		}
+
+		result := analysisResult{Summary: summary, Posts: posts}
+		setCached(ctx, cacheKey, result, ws.config.AnalyzeCacheTTL)
+		return result, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	result := resultAny.(analysisResult)
+	status = "ok"
+
+	c.Header("X-Cache", "MISS")
+	c.JSON(http.StatusOK, gin.H{
+		"summary":   result.Summary,
+		"posts":     result.Posts,
+		"subreddit": subreddit,
+	})
+}
+
+// analysisResult is the cached payload for one (subreddit, model) /analyze
+// result.
+type analysisResult struct {
+	Summary string       `json:"summary"`
+	Posts   []RedditPost `json:"posts"`
+}
+
+// setCacheHeaders sets the Cache-Control header shared by both the cache-hit
+// and cache-miss responses of handleAnalyze.
+func (ws *WebServer) setCacheHeaders(c *gin.Context) {
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(ws.config.AnalyzeCacheTTL.Seconds())))
+}
+
+// handleAnalyzeStream is the Server-Sent Events counterpart to handleAnalyze:
+// instead of buffering the full summary, it streams each token as a
+// "data: ...\n\n" frame as soon as the LLM backend produces it, so slow
+// models like sonnet4 give the browser incremental feedback instead of a
+// blank page for 20+ seconds. Browsers request this via EventSource, which
+// only speaks GET, so unlike /analyze this takes subreddit as a query param.
+func (ws *WebServer) handleAnalyzeStream(c *gin.Context) {
+	subreddit := c.Query("subreddit")
+	if subreddit == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Subreddit name is required"})
+		return
+	}
+	subreddit = strings.TrimPrefix(subreddit, "r/")
+
+	sessionData := ws.getSession(c)
+
 	token, err := getRedditAccessToken()
 	if err != nil {
 		log.Printf("Failed to get access token: %v", err)
@@ -247,34 +757,45 @@ func (ws *WebServer) handleAnalyze(c *gin.Context) {
 		return
 	}
 
-	data, err := subredditData(subreddit, token)
+	data, err := subredditData(c.Request.Context(), subreddit, token)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Generate summary
-	summary, err := summarizePosts(data)
+	backend, err := newLLMBackend(ws.config)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate summary"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Get post links
-	posts, err := fetchTopPosts(subreddit, token)
-	if err != nil {
-		log.Printf("Failed to fetch posts for links: %v", err)
-		posts = []RedditPost{} // Ensure posts is never nil
-	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
 
-	// Format response
-	response := gin.H{
-		"summary":   summary,
-		"posts":     posts,
-		"subreddit": subreddit,
+	prompt := fmt.Sprintf(promptTemplate, subreddit, data)
+	tokens := backend.SummarizeStream(c.Request.Context(), sessionData.Model, prompt)
+
+	for t := range tokens {
+		if t.Err != nil {
+			fmt.Fprintf(c.Writer, "event: error\ndata: %s\n\n", sseEscape(t.Err.Error()))
+			c.Writer.Flush()
+			return
+		}
+		fmt.Fprintf(c.Writer, "data: %s\n\n", sseEscape(t.Text))
+		c.Writer.Flush()
 	}
 
-	c.JSON(http.StatusOK, response)
+	fmt.Fprint(c.Writer, "event: done\ndata: \n\n")
+	c.Writer.Flush()
+}
+
+// sseEscape prevents a literal newline in a streamed fragment from
+// truncating its SSE "data:" frame, which the spec terminates at the first
+// blank line.
+func sseEscape(text string) string {
+	return strings.ReplaceAll(text, "\n", "\\n")
 }
 
 // handleHistory serves the user's history
@@ -50,12 +50,12 @@ func summarizeHandler(w http.ResponseWriter, r *http.Request) {
 		renderPage(w, "", fmt.Sprintf("error getting token: %v", err))
 		return
 	}
-	data, err := subredditData(subreddit, token)
+	data, err := subredditData(r.Context(), subreddit, token)
 	if err != nil {
 		renderPage(w, "", fmt.Sprintf("error fetching data: %v", err))
 		return
 	}
-	summary, err := summarizePosts(data)
+	summary, err := summarizePosts(r.Context(), subreddit, data, getDefaultModelName())
 	if err != nil {
 		renderPage(w, "", fmt.Sprintf("error summarizing: %v", err))
 		return
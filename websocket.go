@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,6 +14,10 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// progressStreamTimeout bounds how long a single streamed AI summary may take
+// before analyzeWithProgress gives up and falls back to the buffered call.
+const progressStreamTimeout = 30 * time.Second
+
 // ProgressMessage represents a progress update sent to the client
 type ProgressMessage struct {
 	Type           string `json:"type"`
@@ -20,6 +26,7 @@ type ProgressMessage struct {
 	Message        string `json:"message"`
 	EstimatedTime  int    `json:"estimated_time,omitempty"`
 	Error          string `json:"error,omitempty"`
+	Token          string `json:"token,omitempty"`
 	Data           interface{} `json:"data,omitempty"`
 }
 
@@ -31,6 +38,7 @@ const (
 	StageFetchingPosts AnalysisStage = "fetching_posts"
 	StageFetchingComments AnalysisStage = "fetching_comments"
 	StageGeneratingSummary AnalysisStage = "generating_summary"
+	StageCached       AnalysisStage = "cached"
 	StageComplete     AnalysisStage = "complete"
 	StageError        AnalysisStage = "error"
 )
@@ -93,6 +101,24 @@ func (pt *ProgressTracker) SendProgress(stage AnalysisStage, progress int, messa
 	return pt.conn.WriteJSON(progressMsg)
 }
 
+// SendToken forwards a single streamed token/fragment of the AI summary to
+// the client as it's generated, so the browser can render output live
+// instead of waiting for the full summary.
+func (pt *ProgressTracker) SendToken(token string) error {
+	pt.mutex.Lock()
+	defer pt.mutex.Unlock()
+
+	pt.conn.SetWriteDeadline(time.Now().Add(30 * time.Second))
+
+	progressMsg := ProgressMessage{
+		Type:  "token",
+		Stage: string(StageGeneratingSummary),
+		Token: token,
+	}
+
+	return pt.conn.WriteJSON(progressMsg)
+}
+
 // SendError sends an error message to the client
 func (pt *ProgressTracker) SendError(err error) error {
 	pt.mutex.Lock()
@@ -182,43 +208,42 @@ func (ws *WebServer) handleWebSocket(c *gin.Context) {
 	// Start analysis with progress tracking
 	if err := ws.analyzeWithProgress(subreddit, sessionData, tracker); err != nil {
 		log.Printf("Analysis failed: %v", err)
-		tracker.SendError(err)
+		tracker.SendError(errors.New(redditErrorMessage(subreddit, err)))
 		return
 	}
 }
 
-// analyzeWithProgress performs subreddit analysis with real-time progress updates
-func (ws *WebServer) analyzeWithProgress(subreddit string, sessionData *Session, tracker *ProgressTracker) error {
-	// Stage 1: Connecting to Reddit
-	tracker.SendProgress(StageConnecting, 5, "Connecting to Reddit...")
-	
-	token, err := getRedditAccessToken()
-	if err != nil {
-		return fmt.Errorf("failed to connect to Reddit: %w", err)
-	}
-
-	// Stage 2: Fetching posts
-	tracker.SendProgress(StageFetchingPosts, 20, "Fetching top posts...")
-	
-	posts, err := fetchTopPosts(subreddit, token)
-	if err != nil {
-		return fmt.Errorf("failed to fetch posts: %w", err)
-	}
-
-	// Stage 3: Fetching comments
-	tracker.SendProgress(StageFetchingComments, 40, fmt.Sprintf("Loading comments for %d posts...", len(posts)))
-	
-	data, err := subredditDataWithProgress(subreddit, token, tracker)
-	if err != nil {
-		return fmt.Errorf("failed to fetch comments: %w", err)
-	}
+// cachedAnalysis is the subreddit analysis result stored under an
+// "analysis:<subreddit>:<model>" cache key, so a repeat request within the
+// TTL window can skip Reddit and the AI model entirely.
+type cachedAnalysis struct {
+	Summary     string
+	SummaryData *SummaryResult
+	Posts       []RedditPost
+}
 
-	// Stage 4: Generating summary
-	tracker.SendProgress(StageGeneratingSummary, 80, "Sending data to AI model...")
-	
-	summary, err := summarizePostsWithProgress(data, sessionData.Model, tracker)
-	if err != nil {
-		return fmt.Errorf("failed to generate summary: %w", err)
+// analyzeWithProgress performs subreddit analysis with real-time progress
+// updates. A cache hit is served immediately as stage "cached"; otherwise
+// the fetch-and-summarize pipeline runs via runAnalysis, coalescing
+// concurrent requests for the same subreddit/model through summaryGroup so
+// only one upstream call is made.
+func (ws *WebServer) analyzeWithProgress(subreddit string, sessionData *Session, tracker *ProgressTracker) error {
+	ctx := context.Background()
+	cacheKey := fmt.Sprintf("analysis:%s:%s", subreddit, sessionData.Model)
+
+	var result cachedAnalysis
+	if getCached(ctx, cacheKey, &result) {
+		log.Printf("INFO: Serving cached analysis for r/%s (model %s)", subreddit, sessionData.Model)
+		tracker.SendProgress(StageCached, 100, fmt.Sprintf("Serving cached analysis for r/%s...", subreddit))
+	} else {
+		resultAny, err, _ := summaryGroup.Do(cacheKey, func() (interface{}, error) {
+			return ws.runAnalysis(subreddit, sessionData.Model, tracker)
+		})
+		if err != nil {
+			return err
+		}
+		result = resultAny.(cachedAnalysis)
+		setCached(ctx, cacheKey, result, AppConfig.CacheTTL)
 	}
 
 	// Add to history
@@ -241,9 +266,10 @@ func (ws *WebServer) analyzeWithProgress(subreddit string, sessionData *Session,
 	// Stage 5: Complete
 	log.Printf("Preparing completion response for subreddit: %s", subreddit)
 	response := gin.H{
-		"summary":   summary,
-		"posts":     posts,
-		"subreddit": subreddit,
+		"summary":      result.Summary,
+		"summary_data": result.SummaryData,
+		"posts":        result.Posts,
+		"subreddit":    subreddit,
 	}
 
 	log.Printf("Sending completion message via WebSocket")
@@ -251,16 +277,55 @@ func (ws *WebServer) analyzeWithProgress(subreddit string, sessionData *Session,
 		log.Printf("Failed to send completion message: %v", err)
 		return err
 	}
-	
+
 	log.Printf("WebSocket analysis completed successfully")
 	return nil
 }
 
+// runAnalysis performs the actual Reddit fetch + summarization pipeline for
+// subreddit, reporting progress via tracker. summaryGroup ensures it only
+// runs once per cacheKey even when multiple callers request it concurrently.
+func (ws *WebServer) runAnalysis(subreddit, model string, tracker *ProgressTracker) (cachedAnalysis, error) {
+	// Stage 1: Connecting to Reddit
+	tracker.SendProgress(StageConnecting, 5, "Connecting to Reddit...")
+
+	token, err := getRedditAccessToken()
+	if err != nil {
+		return cachedAnalysis{}, fmt.Errorf("failed to connect to Reddit: %w", err)
+	}
+
+	// Stage 2: Fetching posts
+	tracker.SendProgress(StageFetchingPosts, 20, "Fetching top posts...")
+
+	posts, err := fetchTopPosts(context.Background(), subreddit, token)
+	if err != nil {
+		return cachedAnalysis{}, fmt.Errorf("failed to fetch posts: %w", err)
+	}
+
+	// Stage 3: Fetching comments
+	tracker.SendProgress(StageFetchingComments, 40, fmt.Sprintf("Loading comments for %d posts...", len(posts)))
+
+	data, err := subredditDataWithProgress(subreddit, token, tracker)
+	if err != nil {
+		return cachedAnalysis{}, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+
+	// Stage 4: Generating summary
+	tracker.SendProgress(StageGeneratingSummary, 80, "Sending data to AI model...")
+
+	summary, summaryData, err := summarizePostsWithProgress(subreddit, data, model, tracker)
+	if err != nil {
+		return cachedAnalysis{}, fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	return cachedAnalysis{Summary: summary, SummaryData: summaryData, Posts: posts}, nil
+}
+
 // subredditDataWithProgress collects subreddit data with progress updates
 func subredditDataWithProgress(subreddit, token string, tracker *ProgressTracker) (string, error) {
 	log.Printf("INFO: Starting data collection for subreddit: r/%s", strings.TrimPrefix(subreddit, "r/"))
 
-	posts, err := fetchTopPosts(subreddit, token)
+	posts, err := fetchTopPosts(context.Background(), subreddit, token)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch posts: %w", err)
 	}
@@ -296,7 +361,7 @@ func subredditDataWithProgress(subreddit, token string, tracker *ProgressTracker
 				fmt.Sprintf("Processing post %d of %d: %s", i+1, len(posts), post.Title[:min(50, len(post.Title))]+"..."))
 
 			log.Printf("INFO: Processing post %d: %s", i+1, post.Title)
-			comments, err := fetchTopComments(post.Permalink, token)
+			comments, err := fetchTopComments(context.Background(), post.Permalink, token)
 			if err != nil {
 				errChan <- fmt.Errorf("failed to fetch comments for post %d: %w", i, err)
 				return
@@ -342,21 +407,77 @@ func subredditDataWithProgress(subreddit, token string, tracker *ProgressTracker
 	return builder.String(), nil
 }
 
-// summarizePostsWithProgress wraps the summarizePosts function with progress tracking
-func summarizePostsWithProgress(text string, model string, tracker *ProgressTracker) (string, error) {
+// summarizePostsWithProgress wraps the OpenAI summarization call with
+// progress tracking, streaming each token to the client via tracker.SendToken
+// as it arrives. If the stream errors, it falls back to the buffered
+// summarizePosts call, even when some tokens were already forwarded. If
+// text is too large for model's
+// context window, it skips streaming entirely and summarizes it in chunks
+// via summarizePostsMapReduce, reporting each chunk's progress instead. It
+// returns both the rendered Markdown and the structured SummaryResult behind
+// it (nil if unavailable, e.g. from the buffered fallback), so the web UI
+// can render proper cards instead of being limited to the rendered string.
+func summarizePostsWithProgress(subreddit, text string, model string, tracker *ProgressTracker) (string, *SummaryResult, error) {
 	log.Printf("Starting AI summarization with model: %s", model)
 	tracker.SendProgress(StageGeneratingSummary, 85, "Processing with AI model...")
-	
-	// Call the original function
-	summary, err := summarizePosts(text, model)
-	if err != nil {
-		log.Printf("AI summarization failed: %v", err)
-		return "", err
+
+	ctx, cancel := context.WithTimeout(context.Background(), progressStreamTimeout)
+	defer cancel()
+
+	maxTokens := maxContextTokensForModel(model) - chunkReserveTokens
+	if estimateTokens(text) > maxTokens {
+		return summarizePostsMapReduce(ctx, subreddit, text, model, func(stage string, current, total int) {
+			switch stage {
+			case "mapping":
+				progress := 85 + (current*10)/total
+				tracker.SendProgress(StageGeneratingSummary, progress, fmt.Sprintf("Summarizing chunk %d/%d...", current, total))
+			case "reducing":
+				tracker.SendProgress(StageGeneratingSummary, 95, "Combining chunk summaries...")
+			}
+		})
 	}
-	
+
+	fragments, errs := summarizePostsStreamOpenAI(ctx, subreddit, text, model)
+
+	var builder strings.Builder
+	for fragments != nil || errs != nil {
+		select {
+		case fragment, ok := <-fragments:
+			if !ok {
+				fragments = nil
+				continue
+			}
+			builder.WriteString(fragment)
+			if err := tracker.SendToken(fragment); err != nil {
+				log.Printf("WARNING: Failed to forward streamed token: %v", err)
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			if err != nil {
+				log.Printf("Streaming AI summarization failed, falling back to non-streaming: %v", err)
+				summary, fallbackErr := summarizePosts(context.Background(), subreddit, text, model)
+				if fallbackErr != nil {
+					return "", nil, fallbackErr
+				}
+				tracker.SendProgress(StageGeneratingSummary, 95, "Finalizing summary...")
+				return summary, nil, nil
+			}
+		}
+	}
+
 	log.Printf("AI summarization completed successfully")
 	tracker.SendProgress(StageGeneratingSummary, 95, "Finalizing summary...")
-	return summary, nil
+
+	content := builder.String()
+	result, err := parseSummaryResultText(content)
+	if err != nil {
+		log.Printf("WARNING: Failed to parse structured summary, returning raw stream output: %v", err)
+		return AppConfig.SummaryHeader + content, nil, nil
+	}
+	return AppConfig.SummaryHeader + result.Markdown(), result, nil
 }
 
 // min returns the minimum of two integers